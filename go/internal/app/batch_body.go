@@ -0,0 +1,81 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"steamid-service/pkg/keyvalues"
+)
+
+// readBatchBody reads a POST batch body capped at MaxBatchBodyBytes. The body is
+// a JSON array of strings when Content-Type is application/json, a Valve KeyValue
+// section when it starts with a quoted name, and newline-delimited text otherwise.
+func readBatchBody(w http.ResponseWriter, r *http.Request, lang string) ([]string, SteamIDError, string) {
+	_, _, maxBodyBytes := batchLimits()
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return nil, ErrorBodyTooLarge, ""
+		}
+		return nil, ErrorInvalidFormat, msg("batch_parse_failed", lang)
+	}
+
+	steamids, ok := parseBatchBody(string(data), r.Header.Get("Content-Type"))
+	if !ok {
+		return nil, ErrorInvalidFormat, msg("batch_parse_failed", lang)
+	}
+	if len(steamids) == 0 {
+		return nil, ErrorMissingParameter, msg("batch_body_empty", lang)
+	}
+
+	return steamids, ErrorNone, ""
+}
+
+func parseBatchBody(body, contentType string) ([]string, bool) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType == "application/json" {
+		var steamids []string
+		if err := json.Unmarshal([]byte(body), &steamids); err != nil {
+			return nil, false
+		}
+		return steamids, true
+	}
+
+	trimmed := strings.TrimSpace(body)
+	if strings.HasPrefix(trimmed, "\"") {
+		return parseKeyValueBatchBody(trimmed)
+	}
+
+	var steamids []string
+	for _, line := range strings.Split(body, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			steamids = append(steamids, line)
+		}
+	}
+	return steamids, true
+}
+
+// parseKeyValueBatchBody reads one `"name" { "key" "value" ... }` section. Each
+// pair contributes its value, or its key when the value is empty or a section, so
+// an indexed list and a previous batch response can both be posted back.
+func parseKeyValueBatchBody(body string) ([]string, bool) {
+	root, err := keyvalues.Unmarshal([]byte(body))
+	if err != nil || !root.Section {
+		return nil, false
+	}
+
+	steamids := make([]string, 0, len(root.Children))
+	for _, child := range root.Children {
+		value := child.Value
+		if child.Section || value == "" {
+			value = child.Key
+		}
+		steamids = append(steamids, value)
+	}
+	return steamids, true
+}
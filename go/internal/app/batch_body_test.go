@@ -0,0 +1,159 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseBatchBodyFormats(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		body        string
+		contentType string
+		want        []string
+		wantOK      bool
+	}{
+		{
+			name:        "newline text with blank lines and CRLF",
+			body:        "76561197960287930\r\n\n  STEAM_1:0:11101 \n",
+			contentType: "text/plain",
+			want:        []string{"76561197960287930", "STEAM_1:0:11101"},
+			wantOK:      true,
+		},
+		{
+			name:   "text starting with a SteamID3 is not JSON",
+			body:   "[U:1:22202]\n[U:1:22203]",
+			want:   []string{"[U:1:22202]", "[U:1:22203]"},
+			wantOK: true,
+		},
+		{
+			name:        "json array",
+			body:        `["76561197960287930","[U:1:22202]"]`,
+			contentType: "application/json; charset=utf-8",
+			want:        []string{"76561197960287930", "[U:1:22202]"},
+			wantOK:      true,
+		},
+		{
+			name:        "json object is rejected",
+			body:        `{"steamid":"1"}`,
+			contentType: "application/json",
+			wantOK:      false,
+		},
+		{
+			name:   "keyvalue indexed list",
+			body:   "\"SteamIDTools\"\n{\n    \"0\" \"76561197960287930\"\n    // comment\n    \"1\" \"STEAM_1:0:11101\"\n}",
+			want:   []string{"76561197960287930", "STEAM_1:0:11101"},
+			wantOK: true,
+		},
+		{
+			name:   "keyvalue keys with empty values",
+			body:   "\"SteamIDTools\" { \"76561197960287930\" \"\" }",
+			want:   []string{"76561197960287930"},
+			wantOK: true,
+		},
+		{
+			name:   "keyvalue escapes and detected sections",
+			body:   "\"SteamIDTools\"\n{\n    \"0\" \"bad\\\"id\"\n    \"[U:1:22202]\"\n    {\n        \"format\" \"sid3\"\n    }\n}",
+			want:   []string{"bad\"id", "[U:1:22202]"},
+			wantOK: true,
+		},
+		{
+			name:   "unterminated keyvalue",
+			body:   "\"SteamIDTools\"\n{\n    \"0\" \"7656",
+			wantOK: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := parseBatchBody(tc.body, tc.contentType)
+			if ok != tc.wantOK {
+				t.Fatalf("expected ok=%v, got %v", tc.wantOK, ok)
+			}
+			if tc.wantOK && !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestHandleConversionPostBatchUsesSeparateLimits(t *testing.T) {
+	previousItems, previousPostItems, previousBytes := appCfg.MaxBatchItems, appCfg.MaxPostBatchItems, appCfg.MaxBatchBodyBytes
+	appCfg.MaxBatchItems = 1
+	appCfg.MaxPostBatchItems = 3
+	appCfg.MaxBatchBodyBytes = 64
+	t.Cleanup(func() {
+		appCfg.MaxBatchItems, appCfg.MaxPostBatchItems, appCfg.MaxBatchBodyBytes = previousItems, previousPostItems, previousBytes
+	})
+
+	testCases := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "within post limit",
+			body:       "76561197960287930\n76561197960287931",
+			wantStatus: http.StatusOK,
+			wantBody:   "\"SteamIDTools\"\n{\n    \"76561197960287930\" \"22202\"\n    \"76561197960287931\" \"22203\"\n}",
+		},
+		{
+			name:       "over post item limit",
+			body:       "1\n2\n3\n4",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "batch size limit exceeded (max 3 items)",
+		},
+		{
+			name:       "over body size cap",
+			body:       strings.Repeat("76561197960287930\n", 4),
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantBody:   "Request body exceeds the configured size limit",
+		},
+		{
+			name:       "empty body",
+			body:       "\n\n",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "request body must contain at least one SteamID",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, EndpointSID64toAID, strings.NewReader(tc.body))
+			rec := httptest.NewRecorder()
+
+			HandleSteamID64ToAccountID(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Fatalf("expected status %d, got %d", tc.wantStatus, rec.Code)
+			}
+			if body := rec.Body.String(); body != tc.wantBody {
+				t.Fatalf("unexpected body %q", body)
+			}
+		})
+	}
+}
+
+func TestHandleConvertPostJSONBodyReturnsJSONBatch(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, EndpointConvert+"?from=auto&to=sid64", strings.NewReader(`["STEAM_1:0:11101","22203"]`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	rec := httptest.NewRecorder()
+
+	HandleConvert(rec, req)
+
+	want := `[{"input":"STEAM_1:0:11101","format":"sid2","value":"76561197960287930"},` +
+		`{"input":"22203","format":"aid","value":"76561197960287931"}]`
+	if body := rec.Body.String(); rec.Code != http.StatusOK || body != want {
+		t.Fatalf("unexpected response %d %q", rec.Code, body)
+	}
+}
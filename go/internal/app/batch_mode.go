@@ -0,0 +1,109 @@
+package app
+
+import (
+	"net/http"
+	"strings"
+)
+
+// batchMode selects how a batch treats duplicates and empty items.
+type batchMode string
+
+const (
+	// batchModeReject fails the whole batch on a repeated item and drops empty items.
+	batchModeReject batchMode = "reject"
+	// batchModeDedupe keeps the first occurrence of each item and counts the rest.
+	batchModeDedupe batchMode = "dedupe"
+	// batchModePositional keeps every slot, empty or repeated, keyed by its index.
+	batchModePositional batchMode = "positional"
+)
+
+// batchOptions is resolved from the mode and identity parameters. Responses only
+// carry the summary block when mode is given, so existing KeyValue parsers see no
+// change. Duplicates is filled in while the items are validated and grouped.
+type batchOptions struct {
+	Mode       batchMode
+	Summary    bool
+	Identity   bool
+	Duplicates int
+}
+
+func resolveBatchOptions(r *http.Request, lang string) (batchOptions, SteamIDError, string) {
+	query := r.URL.Query()
+	var batch batchOptions
+
+	mode := strings.ToLower(strings.TrimSpace(query.Get("mode")))
+	switch batchMode(mode) {
+	case "":
+		batch.Mode = batchModeReject
+	case batchModeReject, batchModeDedupe, batchModePositional:
+		batch.Mode = batchMode(mode)
+		batch.Summary = true
+	default:
+		return batchOptions{}, ErrorInvalidFormat, msgf("batch_mode_invalid", lang, mode)
+	}
+
+	switch identity := strings.ToLower(strings.TrimSpace(query.Get("identity"))); identity {
+	case "", "raw":
+	case "account":
+		batch.Identity = true
+	default:
+		return batchOptions{}, ErrorInvalidFormat, msgf("batch_identity_invalid", lang, identity)
+	}
+
+	return batch, ErrorNone, ""
+}
+
+// groupByAccount groups converted items by the account stored when they were
+// converted so that, for example, STEAM_0:0:11101, STEAM_1:0:11101 and
+// [U:1:22202] count as one player.
+// Cross-format duplicates fail the batch in reject mode, are merged into the
+// first occurrence as aliases in dedupe mode and are kept in positional mode.
+// Items whose account cannot be resolved are left as they are. A rejected batch
+// also returns the input of the duplicate.
+func groupByAccount(results *BatchResult, batch *batchOptions, lang string) (SteamIDError, string, string) {
+	firstByAccount := make(map[string]int, len(results.Items))
+	kept := results.Items[:0]
+	for _, item := range results.Items {
+		account := item.Account
+		if account == "" {
+			kept = append(kept, item)
+			continue
+		}
+
+		first, seen := firstByAccount[account]
+		if !seen {
+			firstByAccount[account] = len(kept)
+			kept = append(kept, item)
+			continue
+		}
+
+		switch batch.Mode {
+		case batchModeReject:
+			return ErrorDuplicateInBatch, msgf("duplicate_account", lang, kept[first].Input, item.Input, account), item.Input
+		case batchModeDedupe:
+			kept[first].Aliases = append(kept[first].Aliases, item.Input)
+			batch.Duplicates++
+			continue
+		}
+		kept = append(kept, item)
+	}
+
+	results.Items = kept
+	results.Identity = true
+	return ErrorNone, "", ""
+}
+
+// newBatchSummary counts the items of a converted batch. Empty positional slots
+// count as failed.
+func newBatchSummary(results BatchResult, duplicates int) BatchSummary {
+	summary := BatchSummary{Total: len(results.Items), Duplicates: duplicates}
+	for _, item := range results.Items {
+		if item.Error.IsValid() {
+			summary.Succeeded++
+		} else {
+			summary.Failed++
+		}
+	}
+
+	return summary
+}
@@ -0,0 +1,176 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandleBatchModes(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		method     string
+		target     string
+		body       string
+		accept     string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "dedupe collapses repeats and counts them",
+			method:     http.MethodGet,
+			target:     EndpointSID64toAID + "?mode=dedupe&steamid=76561197960287930,123,76561197960287930,%2076561197960287930",
+			wantStatus: http.StatusOK,
+			wantBody: "\"SteamIDTools\"\n{\n" +
+				"    \"76561197960287930\" \"22202\"\n" +
+				"    \"123\" \"ERROR: SteamID length is incorrect\"\n" +
+				"    \"summary\"\n    {\n" +
+				"        \"total\" \"2\"\n" +
+				"        \"succeeded\" \"1\"\n" +
+				"        \"failed\" \"1\"\n" +
+				"        \"duplicates\" \"2\"\n" +
+				"    }\n" +
+				"}",
+		},
+		{
+			name:       "positional keeps empty and repeated slots",
+			method:     http.MethodGet,
+			target:     EndpointSID64toAID + "?mode=positional&steamid=76561197960287930,,76561197960287930",
+			wantStatus: http.StatusOK,
+			wantBody: "\"SteamIDTools\"\n{\n" +
+				"    \"0\" \"22202\"\n" +
+				"    \"1\" \"ERROR: Missing required parameter\"\n" +
+				"    \"2\" \"22202\"\n" +
+				"    \"summary\"\n    {\n" +
+				"        \"total\" \"3\"\n" +
+				"        \"succeeded\" \"2\"\n" +
+				"        \"failed\" \"1\"\n" +
+				"        \"duplicates\" \"0\"\n" +
+				"    }\n" +
+				"}",
+		},
+		{
+			name:       "positional detected sections use indexes",
+			method:     http.MethodGet,
+			target:     EndpointConvert + "?from=auto&to=aid&mode=positional&steamid=,[U:1:22202]",
+			wantStatus: http.StatusOK,
+			wantBody: "\"SteamIDTools\"\n{\n" +
+				"    \"0\"\n    {\n        \"format\" \"unknown\"\n        \"value\" \"ERROR: Missing required parameter\"\n    }\n" +
+				"    \"1\"\n    {\n        \"format\" \"sid3\"\n        \"value\" \"22202\"\n    }\n" +
+				"    \"summary\"\n    {\n" +
+				"        \"total\" \"2\"\n" +
+				"        \"succeeded\" \"1\"\n" +
+				"        \"failed\" \"1\"\n" +
+				"        \"duplicates\" \"0\"\n" +
+				"    }\n" +
+				"}",
+		},
+		{
+			name:       "json gets items and summary",
+			method:     http.MethodPost,
+			target:     EndpointSID64toAID + "?mode=dedupe",
+			body:       "76561197960287930\n76561197960287930\n123",
+			accept:     "application/json",
+			wantStatus: http.StatusOK,
+			wantBody: `{"items":[{"input":"76561197960287930","format":"sid64","value":"22202"},` +
+				`{"input":"123","format":"sid64","error":"invalid_length","message":"SteamID length is incorrect"}],` +
+				`"summary":{"total":2,"succeeded":1,"failed":1,"duplicates":1}}`,
+		},
+		{
+			name:       "explicit reject still fails on duplicates",
+			method:     http.MethodGet,
+			target:     EndpointSID64toAID + "?mode=reject&steamid=76561197960287930,76561197960287930",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "Duplicate SteamID found in batch",
+		},
+		{
+			name:       "identity rejects cross-format duplicates",
+			method:     http.MethodGet,
+			target:     EndpointConvert + "?from=auto&to=sid64&identity=account&steamid=STEAM_1:0:11101,[U:1:22202:4]",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "STEAM_1:0:11101 and [U:1:22202:4] refer to the same account (76561197960287930)",
+		},
+		{
+			name:       "identity merges aliases in dedupe mode",
+			method:     http.MethodGet,
+			target:     EndpointConvert + "?from=auto&to=aid&mode=dedupe&identity=account&steamid=STEAM_1:0:11101,76561197960287931,STEAM_0:0:11101,[U:1:22202],bogus",
+			wantStatus: http.StatusOK,
+			wantBody: "\"SteamIDTools\"\n{\n" +
+				"    \"STEAM_1:0:11101\"\n    {\n" +
+				"        \"format\" \"sid2\"\n" +
+				"        \"value\" \"22202\"\n" +
+				"        \"account\" \"76561197960287930\"\n" +
+				"        \"aliases\"\n        {\n" +
+				"            \"0\" \"STEAM_0:0:11101\"\n" +
+				"            \"1\" \"[U:1:22202]\"\n" +
+				"        }\n" +
+				"    }\n" +
+				"    \"76561197960287931\"\n    {\n" +
+				"        \"format\" \"sid64\"\n" +
+				"        \"value\" \"22203\"\n" +
+				"        \"account\" \"76561197960287931\"\n" +
+				"    }\n" +
+				"    \"bogus\"\n    {\n" +
+				"        \"format\" \"unknown\"\n" +
+				"        \"value\" \"ERROR: Invalid SteamID format provided\"\n" +
+				"    }\n" +
+				"    \"summary\"\n    {\n" +
+				"        \"total\" \"3\"\n" +
+				"        \"succeeded\" \"2\"\n" +
+				"        \"failed\" \"1\"\n" +
+				"        \"duplicates\" \"2\"\n" +
+				"    }\n" +
+				"}",
+		},
+		{
+			name:       "identity keeps positional slots with their account",
+			method:     http.MethodGet,
+			target:     EndpointSID2toSID64 + "?mode=positional&identity=account&steamid=STEAM_1:0:11101,STEAM_0:0:11101",
+			accept:     "application/json",
+			wantStatus: http.StatusOK,
+			wantBody: `{"items":[{"input":"STEAM_1:0:11101","format":"sid2","value":"76561197960287930","account":"76561197960287930"},` +
+				`{"input":"STEAM_0:0:11101","format":"sid2","value":"76561197960287930","account":"76561197960287930"}],` +
+				`"summary":{"total":2,"succeeded":2,"failed":0,"duplicates":0}}`,
+		},
+		{
+			name:       "unknown identity",
+			method:     http.MethodGet,
+			target:     EndpointSID64toAID + "?identity=player&steamid=76561197960287930,76561197960287931",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `invalid identity "player" (expected raw or account)`,
+		},
+		{
+			name:       "unknown mode",
+			method:     http.MethodGet,
+			target:     EndpointSID64toAID + "?mode=merge&steamid=76561197960287930,76561197960287931",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `invalid batch mode "merge" (expected reject, dedupe or positional)`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+			req.Header.Set("Accept-Language", "en")
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+			rec := httptest.NewRecorder()
+
+			newHandlerMux(false).ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Fatalf("expected status %d, got %d (%s)", tc.wantStatus, rec.Code, rec.Body.String())
+			}
+			if body := rec.Body.String(); body != tc.wantBody {
+				t.Fatalf("unexpected body:\n%s", body)
+			}
+		})
+	}
+}
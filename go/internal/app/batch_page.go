@@ -0,0 +1,143 @@
+package app
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// batchPaging splits a batch into pages that fit a client buffer, such as the
+// 4096-byte STEAMIDTOOLS_MAX_RESULT_LENGTH of the SourceMod plugin. Cursors are
+// stateless: the client sends the same batch again with the cursor of the
+// previous page, and the digest ties the cursor to that batch.
+type batchPaging struct {
+	PageSize int
+	MaxBytes int
+	Offset   int
+	Digest   string
+}
+
+func (p batchPaging) enabled() bool {
+	return p.PageSize > 0 || p.MaxBytes > 0
+}
+
+func resolveBatchPaging(r *http.Request, lang string) (batchPaging, SteamIDError, string) {
+	query := r.URL.Query()
+	var paging batchPaging
+
+	for _, param := range []struct {
+		name  string
+		value *int
+	}{
+		{name: "page_size", value: &paging.PageSize},
+		{name: "max_bytes", value: &paging.MaxBytes},
+	} {
+		raw := strings.TrimSpace(query.Get(param.name))
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.Atoi(raw)
+		if err != nil || value <= 0 {
+			return batchPaging{}, ErrorInvalidFormat, msgf("batch_page_invalid", lang, param.name, raw)
+		}
+		*param.value = value
+	}
+
+	if cursor := strings.TrimSpace(query.Get("cursor")); cursor != "" {
+		offset, digest, ok := parseBatchCursor(cursor)
+		if !ok {
+			return batchPaging{}, ErrorInvalidFormat, msgf("batch_cursor_invalid", lang, cursor)
+		}
+		paging.Offset = offset
+		paging.Digest = digest
+	}
+
+	return paging, ErrorNone, ""
+}
+
+// batchDigest fingerprints the inputs and the options that shape the item list,
+// so a cursor cannot be replayed against a different batch.
+func batchDigest(steamids []string, cfg conversionHandlerConfig, batch batchOptions) string {
+	hash := fnv.New32a()
+	_, _ = fmt.Fprintf(hash, "%s|%s|%t", cfg.BatchLabel, batch.Mode, batch.Identity)
+	for _, id := range steamids {
+		_, _ = fmt.Fprintf(hash, "\n%s", id)
+	}
+	return fmt.Sprintf("%08x", hash.Sum32())
+}
+
+func formatBatchCursor(offset int, digest string) string {
+	return strconv.Itoa(offset) + "." + digest
+}
+
+func parseBatchCursor(cursor string) (int, string, bool) {
+	rawOffset, digest, ok := strings.Cut(cursor, ".")
+	if !ok || len(digest) != 8 {
+		return 0, "", false
+	}
+	offset, err := strconv.Atoi(rawOffset)
+	if err != nil || offset < 0 {
+		return 0, "", false
+	}
+	return offset, digest, true
+}
+
+// terminatedSize is the buffer a client needs for body as a C string: the body
+// plus its NUL terminator, which nullterm=1 already appended.
+func terminatedSize(body []byte) int {
+	if len(body) > 0 && body[len(body)-1] == 0 {
+		return len(body)
+	}
+	return len(body) + 1
+}
+
+// page returns the slice of results starting at the cursor offset. Pages hold at
+// most PageSize items and, when MaxBytes is set, as many items as fit once
+// encoded in a MaxBytes buffer together with the NUL terminator; a page always
+// holds at least one item so the client makes progress.
+// The summary block is only sent with the last page.
+func (p batchPaging) page(results BatchResult, digest string, encode func(BatchResult) ([]byte, string, error), lang string) (BatchResult, SteamIDError, string) {
+	if p.Digest != "" && (p.Digest != digest || p.Offset > len(results.Items)) {
+		return BatchResult{}, ErrorInvalidFormat, msgf("batch_cursor_invalid", lang, formatBatchCursor(p.Offset, p.Digest))
+	}
+	if !p.enabled() && p.Offset == 0 {
+		return results, ErrorNone, ""
+	}
+
+	all := results.Items
+	build := func(end int) BatchResult {
+		page := results
+		page.Paged = true
+		page.Offset = p.Offset
+		page.Items = all[p.Offset:end]
+		page.Cursor = ""
+		if end < len(all) {
+			page.Cursor = formatBatchCursor(end, digest)
+			page.Summary = nil
+		}
+		return page
+	}
+
+	end := len(all)
+	if p.PageSize > 0 && p.Offset+p.PageSize < end {
+		end = p.Offset + p.PageSize
+	}
+	if p.MaxBytes > 0 && end-p.Offset > 1 {
+		fits := func(end int) bool {
+			body, _, err := encode(build(end))
+			return err == nil && terminatedSize(body) <= p.MaxBytes
+		}
+		if !fits(end) {
+			// Largest item count in [1, end-offset) whose page still fits.
+			count := sort.Search(end-p.Offset-1, func(n int) bool {
+				return !fits(p.Offset + n + 2)
+			})
+			end = p.Offset + count + 1
+		}
+	}
+
+	return build(end), ErrorNone, ""
+}
@@ -0,0 +1,222 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"steamid-service/pkg/keyvalues"
+)
+
+func TestBatchPaginationWalksEveryPage(t *testing.T) {
+	t.Parallel()
+
+	steamids := make([]string, 0, 20)
+	for i := 0; i < 20; i++ {
+		steamids = append(steamids, strconv.FormatUint(76561197960287930+uint64(i), 10))
+	}
+
+	testCases := []struct {
+		name  string
+		query string
+		check func(t *testing.T, body string, items int)
+	}{
+		{
+			name:  "max bytes",
+			query: "max_bytes=160",
+			check: func(t *testing.T, body string, items int) {
+				if len(body) > 159 {
+					t.Fatalf("page of %d bytes leaves no room for the terminator in max_bytes", len(body))
+				}
+			},
+		},
+		{
+			name:  "page size",
+			query: "page_size=6",
+			check: func(t *testing.T, body string, items int) {
+				if items > 6 {
+					t.Fatalf("page has %d items", items)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got []string
+			cursor := ""
+			for pages := 0; ; pages++ {
+				if pages > len(steamids) {
+					t.Fatal("pagination did not terminate")
+				}
+				target := EndpointSID64toAID + "?" + tc.query + "&steamid=" + strings.Join(steamids, ",")
+				if cursor != "" {
+					target += "&cursor=" + url.QueryEscape(cursor)
+				}
+				rec := httptest.NewRecorder()
+				HandleSteamID64ToAccountID(rec, httptest.NewRequest(http.MethodGet, target, nil))
+				if rec.Code != http.StatusOK {
+					t.Fatalf("expected status %d, got %d (%s)", http.StatusOK, rec.Code, rec.Body.String())
+				}
+
+				root, err := keyvalues.Unmarshal(rec.Body.Bytes())
+				if err != nil {
+					t.Fatalf("invalid page: %v", err)
+				}
+				cursor = ""
+				items := 0
+				for _, child := range root.Children {
+					if child.Key == "cursor" {
+						cursor = child.Value
+						continue
+					}
+					got = append(got, child.Key)
+					items++
+				}
+				tc.check(t, rec.Body.String(), items)
+				if cursor == "" {
+					break
+				}
+			}
+
+			if strings.Join(got, ",") != strings.Join(steamids, ",") {
+				t.Fatalf("pages returned %v", got)
+			}
+		})
+	}
+}
+
+func TestBatchPaginationReservesTerminator(t *testing.T) {
+	t.Parallel()
+
+	results := BatchResult{Items: make([]BatchItemResult, 8)}
+	testCases := []struct {
+		name      string
+		nullTerm  bool
+		wantItems int
+	}{
+		// Four items encode to exactly 4096 bytes, which leaves no room for the
+		// NUL that STEAMIDTOOLS_MAX_RESULT_LENGTH must also hold.
+		{name: "terminator added by the client", wantItems: 3},
+		{name: "terminator sent with nullterm", nullTerm: true, wantItems: 4},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			encode := func(page BatchResult) ([]byte, string, error) {
+				body := []byte(strings.Repeat("x", 1024*len(page.Items)))
+				if tc.nullTerm {
+					body[len(body)-1] = 0
+				}
+				return body, contentTypePlainText, nil
+			}
+			page, pageErr, _ := batchPaging{MaxBytes: 4096}.page(results, "00000000", encode, "en")
+			if !pageErr.IsValid() || len(page.Items) != tc.wantItems {
+				t.Fatalf("expected %d items, got %d (%s)", tc.wantItems, len(page.Items), pageErr)
+			}
+		})
+	}
+}
+
+func TestBatchPaginationResponses(t *testing.T) {
+	t.Parallel()
+
+	const batch = "steamid=76561197960287930,,123"
+	first := EndpointSID64toAID + "?mode=positional&page_size=2&" + batch
+	firstRec := httptest.NewRecorder()
+	HandleSteamID64ToAccountID(firstRec, httptest.NewRequest(http.MethodGet, first, nil))
+	root, err := keyvalues.Unmarshal(firstRec.Body.Bytes())
+	if err != nil {
+		t.Fatalf("invalid first page: %v", err)
+	}
+	cursor := root.String("cursor")
+	if !strings.HasPrefix(cursor, "2.") || len(root.Children) != 3 {
+		t.Fatalf("unexpected first page:\n%s", firstRec.Body.String())
+	}
+
+	testCases := []struct {
+		name       string
+		target     string
+		accept     string
+		wantStatus int
+		wantBody   string
+		wantPrefix bool
+	}{
+		{
+			name:       "positional keys continue and summary ends the batch",
+			target:     first + "&cursor=" + cursor,
+			wantStatus: http.StatusOK,
+			wantBody: "\"SteamIDTools\"\n{\n" +
+				"    \"2\" \"ERROR: SteamID length is incorrect\"\n" +
+				"    \"summary\"\n    {\n" +
+				"        \"total\" \"3\"\n" +
+				"        \"succeeded\" \"1\"\n" +
+				"        \"failed\" \"2\"\n" +
+				"        \"duplicates\" \"0\"\n" +
+				"    }\n" +
+				"}",
+		},
+		{
+			name:       "json pages carry the cursor",
+			target:     EndpointSID64toAID + "?page_size=1&steamid=76561197960287930,76561197960287931",
+			accept:     "application/json",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"items":[{"input":"76561197960287930","format":"sid64","value":"22202"}],"cursor":"1.`,
+			wantPrefix: true,
+		},
+		{
+			name:       "cursor from another batch",
+			target:     EndpointSID64toAID + "?page_size=2&steamid=76561197960287930,76561197960287931&cursor=" + cursor,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `invalid cursor "` + cursor + `" for this batch`,
+		},
+		{
+			name:       "malformed cursor",
+			target:     first + "&cursor=abc",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `invalid cursor "abc" for this batch`,
+		},
+		{
+			name:       "invalid page size",
+			target:     EndpointSID64toAID + "?page_size=0&" + batch,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `invalid page_size "0" (expected a positive integer)`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			req.Header.Set("Accept-Language", "en")
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+			rec := httptest.NewRecorder()
+
+			HandleSteamID64ToAccountID(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Fatalf("expected status %d, got %d (%s)", tc.wantStatus, rec.Code, rec.Body.String())
+			}
+			body := rec.Body.String()
+			if tc.wantPrefix && strings.HasPrefix(body, tc.wantBody) {
+				return
+			}
+			if body != tc.wantBody {
+				t.Fatalf("unexpected body:\n%s", body)
+			}
+		})
+	}
+}
@@ -1,44 +1,503 @@
 package app
 
 import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rs/zerolog"
 )
 
 type appConfig struct {
-	Debug         bool
-	Host          string
-	Port          string
-	SID2Universe  string
-	MaxBatchItems int
-	BackendLang   string
+	ConfigFile         string
+	Debug              bool
+	LogLevel           string
+	Host               string
+	Port               string
+	MetricsAddr        string
+	ShutdownTimeout    time.Duration
+	ShutdownDelay      time.Duration
+	SID2Universe       string
+	BackendLang        string
+	MaxBatchItems      int
+	MaxPostBatchItems  int
+	MaxBatchBodyBytes  int64
+	JobTTL             time.Duration
+	JobStoreDir        string
+	CORSAllowOrigin    string
+	OTLPEndpoint       string
+	OTLPTracesEndpoint string
+	TraceServiceName   string
+	// Sources records where each setting's value came from, by setting key.
+	Sources map[string]configSource
+}
+
+// configSource is where a setting's value came from: "default", "file", "env"
+// or "flag", with the file line, variable or flag that set it.
+type configSource struct {
+	Name   string
+	Origin string
+}
+
+// configProblem is one rejected setting. Message is a backend message key, so
+// the text follows BACKEND_LANG when the problem is reported.
+type configProblem struct {
+	Origin  string
+	Key     string
+	Message string
+	Args    []any
+}
+
+func (p configProblem) Error() string {
+	message := msgBackend(p.Message, p.Args...)
+	if p.Key != "" {
+		message = p.Key + ": " + message
+	}
+	if p.Origin != "" {
+		message = p.Origin + ": " + message
+	}
+	return message
 }
 
+// configErrors is every problem found while loading the configuration.
+type configErrors []configProblem
+
+func (e configErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, problem := range e {
+		messages[i] = problem.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// appCfgMu guards the settings SIGHUP can change while requests read them; the
+// rest of appCfg is only written before the servers start.
+var appCfgMu sync.RWMutex
+
 var appCfg = loadConfigFromEnv()
 
-func loadConfigFromEnv() appConfig {
-	cfg := appConfig{
-		Debug:         os.Getenv("DEBUG") == "1",
-		Host:          envOrDefault("HOST", "0.0.0.0"),
-		Port:          envOrDefault("PORT", "80"),
-		SID2Universe:  envOrDefault("SID2_UNIVERSE", SID2_UNIVERSE),
-		MaxBatchItems: 32,
-		BackendLang:   envOrDefault("BACKEND_LANG", "en"),
+func defaultConfig() appConfig {
+	return appConfig{
+		Host:              "0.0.0.0",
+		Port:              "80",
+		ShutdownDelay:     2 * time.Second, // Docker kills the container 10s after SIGTERM,
+		ShutdownTimeout:   6 * time.Second, // so delay plus timeout stays under it.
+		SID2Universe:      SID2_UNIVERSE,
+		BackendLang:       "en",
+		MaxBatchItems:     32,
+		MaxPostBatchItems: 10000,
+		MaxBatchBodyBytes: 1 << 20,
+		JobTTL:            10 * time.Minute,
+		CORSAllowOrigin:   "*",
+		TraceServiceName:  "steamid-service",
 	}
+}
+
+// configSetting is one setting and every place it can come from: a key in the
+// config file, an environment variable and a command-line flag.
+type configSetting struct {
+	Key    string
+	Env    string
+	Flag   string
+	Usage  string
+	Reload bool
+	// ZeroOK allows 0 for numbers and durations, which must otherwise be positive.
+	ZeroOK bool
+	// Values lists the accepted values of a string setting; nil accepts any.
+	Values []string
+	// check validates a string setting beyond Values.
+	check func(value string) *configProblem
+	field func(cfg *appConfig) any
+}
+
+var configSettings = []configSetting{
+	{Key: "server.host", Env: "HOST", Flag: "host", Usage: "Listen host",
+		field: func(cfg *appConfig) any { return &cfg.Host }},
+	{Key: "server.port", Env: "PORT", Flag: "port", Usage: "Listen port",
+		check: checkPort,
+		field: func(cfg *appConfig) any { return &cfg.Port }},
+	{Key: "server.metrics_addr", Env: "METRICS_ADDR", Flag: "metrics-addr", Usage: "Separate listen address for /metrics",
+		check: checkListenAddr,
+		field: func(cfg *appConfig) any { return &cfg.MetricsAddr }},
+	{Key: "server.shutdown_timeout", Env: "SHUTDOWN_TIMEOUT", Flag: "shutdown-timeout", Usage: "How long requests and jobs may drain on shutdown",
+		field: func(cfg *appConfig) any { return &cfg.ShutdownTimeout }},
+	{Key: "server.shutdown_delay", Env: "SHUTDOWN_DELAY", Flag: "shutdown-delay", Usage: "How long /health answers 503 before the listener closes", ZeroOK: true,
+		field: func(cfg *appConfig) any { return &cfg.ShutdownDelay }},
+	{Key: "steamid.sid2_universe", Env: "SID2_UNIVERSE", Flag: "sid2-universe", Usage: "Default SteamID2 universe (0 or 1)",
+		Values: []string{"0", "1"},
+		field:  func(cfg *appConfig) any { return &cfg.SID2Universe }},
+	{Key: "batch.max_items", Env: "MAX_BATCH_ITEMS", Flag: "max-batch-items", Usage: "Maximum items per comma-separated batch", Reload: true,
+		field: func(cfg *appConfig) any { return &cfg.MaxBatchItems }},
+	{Key: "batch.max_post_items", Env: "MAX_POST_BATCH_ITEMS", Flag: "max-post-batch-items", Usage: "Maximum items per POST batch body", Reload: true,
+		field: func(cfg *appConfig) any { return &cfg.MaxPostBatchItems }},
+	{Key: "batch.max_body_bytes", Env: "MAX_BATCH_BODY_BYTES", Flag: "max-batch-body-bytes", Usage: "Maximum POST batch body size in bytes", Reload: true,
+		field: func(cfg *appConfig) any { return &cfg.MaxBatchBodyBytes }},
+	{Key: "batch.job_ttl", Env: "JOB_TTL", Flag: "job-ttl", Usage: "Lifetime of asynchronous batch jobs",
+		field: func(cfg *appConfig) any { return &cfg.JobTTL }},
+	{Key: "batch.job_store_dir", Env: "JOB_STORE_DIR", Flag: "job-store-dir", Usage: "Directory for on-disk job storage",
+		field: func(cfg *appConfig) any { return &cfg.JobStoreDir }},
+	{Key: "i18n.backend_lang", Env: "BACKEND_LANG", Flag: "backend-lang", Usage: "Backend language (en/es)", Reload: true,
+		Values: []string{"en", "es"},
+		field:  func(cfg *appConfig) any { return &cfg.BackendLang }},
+	{Key: "cors.allow_origin", Env: "CORS_ALLOW_ORIGIN", Flag: "cors-allow-origin", Usage: "Access-Control-Allow-Origin value; empty disables CORS",
+		check: checkCORSOrigin,
+		field: func(cfg *appConfig) any { return &cfg.CORSAllowOrigin }},
+	{Key: "log.level", Env: "LOG_LEVEL", Flag: "log-level", Usage: "Log level (debug, info, warn, error); debug when unset and debug mode is on", Reload: true,
+		Values: []string{"debug", "info", "warn", "error"},
+		field:  func(cfg *appConfig) any { return &cfg.LogLevel }},
+	{Key: "log.debug", Env: "DEBUG", Flag: "debug", Usage: "Debug mode: request dumps, caller info and /debug",
+		field: func(cfg *appConfig) any { return &cfg.Debug }},
+	{Key: "tracing.otlp_endpoint", Env: "OTEL_EXPORTER_OTLP_ENDPOINT", Flag: "otlp-endpoint", Usage: "OTLP/HTTP collector base URL; /v1/traces is appended",
+		check: checkHTTPURL,
+		field: func(cfg *appConfig) any { return &cfg.OTLPEndpoint }},
+	{Key: "tracing.otlp_traces_endpoint", Env: "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", Flag: "otlp-traces-endpoint", Usage: "Full OTLP/HTTP traces URL",
+		check: checkHTTPURL,
+		field: func(cfg *appConfig) any { return &cfg.OTLPTracesEndpoint }},
+	{Key: "tracing.service_name", Env: "OTEL_SERVICE_NAME", Flag: "otel-service-name", Usage: "service.name of exported spans",
+		field: func(cfg *appConfig) any { return &cfg.TraceServiceName }},
+}
+
+var logLevels = map[string]zerolog.Level{
+	"debug": zerolog.DebugLevel,
+	"info":  zerolog.InfoLevel,
+	"warn":  zerolog.WarnLevel,
+	"error": zerolog.ErrorLevel,
+}
 
-	if val := os.Getenv("MAX_BATCH_ITEMS"); val != "" {
-		if n, err := strconv.Atoi(val); err == nil && n > 0 {
-			cfg.MaxBatchItems = n
+// set parses value into the setting's field of cfg. A rejected value leaves
+// the field unchanged.
+func (s configSetting) set(cfg *appConfig, value string) *configProblem {
+	switch field := s.field(cfg).(type) {
+	case *string:
+		if s.Values != nil && !slices.Contains(s.Values, value) {
+			return &configProblem{Message: "config_not_one_of", Args: []any{value, strings.Join(s.Values, ", ")}}
+		}
+		if s.check != nil {
+			if problem := s.check(value); problem != nil {
+				return problem
+			}
+		}
+		*field = value
+	case *bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return &configProblem{Message: "config_not_boolean", Args: []any{value}}
+		}
+		*field = b
+	case *int:
+		n, err := strconv.Atoi(value)
+		if err != nil || !s.inRange(int64(n)) {
+			return s.rangeProblem(value, false)
 		}
+		*field = n
+	case *int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || !s.inRange(n) {
+			return s.rangeProblem(value, false)
+		}
+		*field = n
+	case *time.Duration:
+		d, err := time.ParseDuration(value)
+		if err != nil || !s.inRange(int64(d)) {
+			return s.rangeProblem(value, true)
+		}
+		*field = d
 	}
+	return nil
+}
 
+func (s configSetting) inRange(n int64) bool {
+	return n > 0 || (n == 0 && s.ZeroOK)
+}
+
+// rangeProblem reports a number or duration that is not positive, or that is
+// negative for settings that allow zero.
+func (s configSetting) rangeProblem(value string, isDuration bool) *configProblem {
+	message := "config_not_positive_integer"
+	switch {
+	case isDuration && s.ZeroOK:
+		message = "config_not_non_negative_duration"
+	case isDuration:
+		message = "config_not_positive_duration"
+	case s.ZeroOK:
+		message = "config_not_non_negative_integer"
+	}
+	return &configProblem{Message: message, Args: []any{value}}
+}
+
+func checkPort(value string) *configProblem {
+	if port, err := strconv.Atoi(value); err != nil || port < 1 || port > 65535 {
+		return &configProblem{Message: "config_invalid_port", Args: []any{value}}
+	}
+	return nil
+}
+
+// checkListenAddr accepts host:port, or empty for settings that are off when unset.
+func checkListenAddr(value string) *configProblem {
+	if value == "" {
+		return nil
+	}
+	_, port, err := net.SplitHostPort(value)
+	if err != nil || checkPort(port) != nil {
+		return &configProblem{Message: "config_invalid_address", Args: []any{value}}
+	}
+	return nil
+}
+
+func checkHTTPURL(value string) *configProblem {
+	if value == "" {
+		return nil
+	}
+	parsed, err := url.Parse(value)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return &configProblem{Message: "config_invalid_url", Args: []any{value}}
+	}
+	return nil
+}
+
+// checkCORSOrigin accepts "*", empty or a single origin; browsers reject
+// anything else in Access-Control-Allow-Origin.
+func checkCORSOrigin(value string) *configProblem {
+	if value == "*" || value == "" {
+		return nil
+	}
+	parsed, err := url.Parse(value)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" ||
+		(parsed.Path != "" && parsed.Path != "/") || parsed.RawQuery != "" || parsed.Fragment != "" {
+		return &configProblem{Message: "config_invalid_origin", Args: []any{value}}
+	}
+	return nil
+}
+
+// get formats the setting's field of cfg the way set reads it.
+func (s configSetting) get(cfg *appConfig) string {
+	switch field := s.field(cfg).(type) {
+	case *string:
+		return *field
+	case *bool:
+		return strconv.FormatBool(*field)
+	case *int:
+		return strconv.Itoa(*field)
+	case *int64:
+		return strconv.FormatInt(*field, 10)
+	case *time.Duration:
+		return field.String()
+	}
+	return ""
+}
+
+func (s configSetting) isBool() bool {
+	_, ok := s.field(&appConfig{}).(*bool)
+	return ok
+}
+
+// loadConfigFromEnv is the configuration before Run parses flags and the config
+// file; invalid variables keep their defaults here and fail Run later.
+func loadConfigFromEnv() appConfig {
+	cfg := defaultConfig()
+	for _, setting := range configSettings {
+		if value := os.Getenv(setting.Env); value != "" {
+			_ = setting.set(&cfg, value)
+		}
+	}
 	return cfg
 }
 
-func envOrDefault(key, fallback string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+// loadConfig layers the defaults, the config file, the environment and the flags
+// in args, each overriding the one before. The file comes from -config or
+// CONFIG_FILE. Every invalid value is reported in a configErrors; the returned
+// config then holds the values that were accepted.
+func loadConfig(args []string, getenv func(string) string) (appConfig, error) {
+	flagValues, configFile, err := parseConfigFlags(args)
+	if err != nil {
+		return appConfig{}, err
+	}
+	if configFile == "" {
+		configFile = getenv("CONFIG_FILE")
+	}
+
+	cfg := defaultConfig()
+	cfg.ConfigFile = configFile
+	cfg.Sources = make(map[string]configSource, len(configSettings))
+	for _, setting := range configSettings {
+		cfg.Sources[setting.Key] = configSource{Name: "default"}
+	}
+
+	var problems configErrors
+	apply := func(setting configSetting, value string, source configSource) {
+		if problem := setting.set(&cfg, value); problem != nil {
+			problem.Origin, problem.Key = source.Origin, setting.Key
+			problems = append(problems, *problem)
+			return
+		}
+		cfg.Sources[setting.Key] = source
+	}
+
+	if configFile != "" {
+		fileValues, err := readConfigFile(configFile)
+		var fileProblems configErrors
+		if errors.As(err, &fileProblems) {
+			problems = append(problems, fileProblems...)
+		} else if err != nil {
+			return cfg, err
+		}
+		for _, setting := range configSettings {
+			if value, ok := fileValues[setting.Key]; ok {
+				apply(setting, value.Value, configSource{Name: "file", Origin: fmt.Sprintf("%s:%d", configFile, value.Line)})
+			}
+		}
+	}
+
+	for _, setting := range configSettings {
+		if value := getenv(setting.Env); value != "" {
+			apply(setting, value, configSource{Name: "env", Origin: setting.Env})
+		}
+	}
+
+	for _, setting := range configSettings {
+		if value, ok := flagValues[setting.Flag]; ok {
+			apply(setting, value, configSource{Name: "flag", Origin: "-" + setting.Flag})
+		}
+	}
+
+	if len(problems) > 0 {
+		return cfg, problems
+	}
+	return cfg, nil
+}
+
+// runCheckConfigCommand loads the configuration the way Run does and prints
+// each setting's effective value and where it came from, then every problem.
+func runCheckConfigCommand(args []string, getenv func(string) string, stdout io.Writer) error {
+	cfg, err := loadConfig(args, getenv)
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	var problems configErrors
+	if err != nil && !errors.As(err, &problems) {
+		return err
+	}
+	loadBackendMessages(cfg.BackendLang)
+
+	table := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(table, msgBackend("config_check_header"))
+	for _, setting := range configSettings {
+		value := setting.get(&cfg)
+		if value == "" {
+			value = `""`
+		}
+		source := cfg.Sources[setting.Key]
+		label := source.Name
+		if source.Origin != "" {
+			label += " (" + source.Origin + ")"
+		}
+		_, _ = fmt.Fprintf(table, "%s\t%s\t%s\n", setting.Key, value, label)
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			_, _ = fmt.Fprintf(stdout, "❌ %s\n", problem.Error())
+		}
+		return errors.New(msgBackend("config_check_failed", len(problems)))
+	}
+	_, _ = fmt.Fprintln(stdout, msgBackend("config_check_ok"))
+	return nil
+}
+
+// parseConfigFlags returns the setting flags present in args by flag name, and
+// the -config path.
+func parseConfigFlags(args []string) (map[string]string, string, error) {
+	fs := flag.NewFlagSet("steamid-service", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	configFile := fs.String("config", "", "Path of a TOML config file")
+
+	values := make(map[string]string)
+	for _, setting := range configSettings {
+		record := func(value string) error {
+			values[setting.Flag] = value
+			return nil
+		}
+		if setting.isBool() {
+			fs.BoolFunc(setting.Flag, setting.Usage, record)
+			continue
+		}
+		fs.Func(setting.Flag, setting.Usage, record)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fs.SetOutput(os.Stderr)
+			fs.PrintDefaults()
+		}
+		return nil, "", err
+	}
+	return values, *configFile, nil
+}
+
+// zerologLevel is the configured log level; without one, debug mode logs at
+// debug and everything else at info.
+func (cfg appConfig) zerologLevel() zerolog.Level {
+	if level, ok := logLevels[cfg.LogLevel]; ok {
+		return level
+	}
+	if cfg.Debug {
+		return zerolog.DebugLevel
+	}
+	return zerolog.InfoLevel
+}
+
+// tracesEndpoint resolves the OTLP/HTTP traces URL the way OpenTelemetry SDKs
+// do; tracing stays off when neither endpoint is set.
+func (cfg appConfig) tracesEndpoint() string {
+	if cfg.OTLPTracesEndpoint != "" {
+		return cfg.OTLPTracesEndpoint
+	}
+	if cfg.OTLPEndpoint != "" {
+		return strings.TrimRight(cfg.OTLPEndpoint, "/") + "/v1/traces"
+	}
+	return ""
+}
+
+// batchLimits returns the batch limits, which SIGHUP can change.
+func batchLimits() (maxItems, maxPostItems int, maxBodyBytes int64) {
+	appCfgMu.RLock()
+	defer appCfgMu.RUnlock()
+	return appCfg.MaxBatchItems, appCfg.MaxPostBatchItems, appCfg.MaxBatchBodyBytes
+}
+
+// reloadConfig applies the reloadable settings of cfg to appCfg. It returns the
+// keys it changed and the keys that differ but only take effect on restart.
+func reloadConfig(cfg appConfig) (changed, restart []string) {
+	appCfgMu.Lock()
+	for _, setting := range configSettings {
+		value := setting.get(&cfg)
+		if value == setting.get(&appCfg) {
+			continue
+		}
+		if !setting.Reload {
+			restart = append(restart, setting.Key)
+			continue
+		}
+		_ = setting.set(&appCfg, value)
+		changed = append(changed, setting.Key)
 	}
+	level, lang := appCfg.zerologLevel(), appCfg.BackendLang
+	appCfgMu.Unlock()
 
-	return fallback
+	zerolog.SetGlobalLevel(level)
+	loadBackendMessages(lang)
+	return changed, restart
 }
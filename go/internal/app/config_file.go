@@ -0,0 +1,215 @@
+package app
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// The config file is TOML restricted to what the settings need: [section]
+// tables and key = value pairs whose values are strings, integers or booleans,
+// with # comments. Durations are strings such as "10m". Strings follow the TOML
+// rules: "basic" strings take TOML escapes and 'literal' strings none.
+
+var (
+	configTablePattern = regexp.MustCompile(`^\[([a-z0-9_]+)\]$`)
+	configKeyPattern   = regexp.MustCompile(`^[a-z0-9_]+$`)
+	configIntPattern   = regexp.MustCompile(`^[+-]?[0-9]+(_[0-9]+)*$`) // underscores only between digits, as in TOML
+)
+
+// configFileValue is a value read from the config file and the line it is on.
+type configFileValue struct {
+	Value string
+	Line  int
+}
+
+// readConfigFile reads a config file into values by "section.key".
+func readConfigFile(path string) (map[string]configFileValue, error) {
+	file, err := os.Open(path) // #nosec G304 -- the path comes from the operator (-config or CONFIG_FILE).
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	return parseConfigFile(path, file)
+}
+
+// parseConfigFile reads every line of r and reports all malformed lines, not
+// just the first; name locates them in the problems.
+func parseConfigFile(name string, r io.Reader) (map[string]configFileValue, error) {
+	known := make(map[string]bool, len(configSettings))
+	for _, setting := range configSettings {
+		known[setting.Key] = true
+	}
+
+	values := make(map[string]configFileValue)
+	var problems configErrors
+	section := ""
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		origin := fmt.Sprintf("%s:%d", name, lineNumber)
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if match := configTablePattern.FindStringSubmatch(line); match != nil {
+			section = match[1]
+			continue
+		}
+
+		key, rawValue, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !configKeyPattern.MatchString(key) {
+			problems = append(problems, configProblem{Origin: origin, Message: "config_invalid_line"})
+			continue
+		}
+
+		if section != "" {
+			key = section + "." + key
+		}
+		if !known[key] {
+			problems = append(problems, configProblem{Origin: origin, Message: "config_unknown_setting", Args: []any{key}})
+			continue
+		}
+		if previous, duplicate := values[key]; duplicate {
+			problems = append(problems, configProblem{Origin: origin, Key: key, Message: "config_duplicate_setting", Args: []any{previous.Line}})
+			continue
+		}
+
+		value, problem := parseConfigValue(strings.TrimSpace(rawValue))
+		if problem != nil {
+			problem.Origin, problem.Key = origin, key
+			problems = append(problems, *problem)
+			continue
+		}
+		values[key] = configFileValue{Value: value, Line: lineNumber}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(problems) > 0 {
+		return values, problems
+	}
+	return values, nil
+}
+
+// parseConfigValue reads one TOML value, dropping a trailing comment.
+func parseConfigValue(raw string) (string, *configProblem) {
+	switch {
+	case strings.HasPrefix(raw, `"`):
+		end := closingQuote(raw)
+		if end < 0 {
+			return "", &configProblem{Message: "config_unterminated_string"}
+		}
+		if problem := onlyComment(raw[end+1:]); problem != nil {
+			return "", problem
+		}
+		return unescapeBasicString(raw[1:end])
+	case strings.HasPrefix(raw, "'"):
+		end := strings.IndexByte(raw[1:], '\'')
+		if end < 0 {
+			return "", &configProblem{Message: "config_unterminated_string"}
+		}
+		if problem := onlyComment(raw[end+2:]); problem != nil {
+			return "", problem
+		}
+		if problem := checkControlCharacters(raw[1 : end+1]); problem != nil {
+			return "", problem
+		}
+		return raw[1 : end+1], nil
+	}
+
+	value, _, _ := strings.Cut(raw, "#")
+	value = strings.TrimSpace(value)
+	switch {
+	case value == "true" || value == "false":
+		return value, nil
+	case configIntPattern.MatchString(value):
+		return strings.ReplaceAll(strings.TrimPrefix(value, "+"), "_", ""), nil
+	}
+	return "", &configProblem{Message: "config_unsupported_value", Args: []any{value}}
+}
+
+// closingQuote returns the index of the quote ending the basic string at the
+// start of raw, skipping escaped quotes.
+func closingQuote(raw string) int {
+	for i := 1; i < len(raw); i++ {
+		switch raw[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// unescapeBasicString applies the escapes of a TOML basic string. Unlike Go
+// literals, \xHH and \uHHHH name Unicode code points and \e is escape.
+func unescapeBasicString(body string) (string, *configProblem) {
+	if problem := checkControlCharacters(body); problem != nil {
+		return "", problem
+	}
+
+	var value strings.Builder
+	for i := 0; i < len(body); i++ {
+		if body[i] != '\\' {
+			value.WriteByte(body[i])
+			continue
+		}
+		if i+1 == len(body) {
+			return "", &configProblem{Message: "config_invalid_escape", Args: []any{body[i:]}}
+		}
+
+		i++
+		if replacement, ok := configEscapes[body[i]]; ok {
+			value.WriteByte(replacement)
+			continue
+		}
+		digits := configHexEscapes[body[i]]
+		if digits == 0 || i+digits >= len(body) {
+			return "", &configProblem{Message: "config_invalid_escape", Args: []any{body[i-1 : min(i+1+digits, len(body))]}}
+		}
+		escape := body[i-1 : i+1+digits]
+		code, err := strconv.ParseUint(body[i+1:i+1+digits], 16, 32)
+		if err != nil || !utf8.ValidRune(rune(code)) {
+			return "", &configProblem{Message: "config_invalid_escape", Args: []any{escape}}
+		}
+		value.WriteRune(rune(code))
+		i += digits
+	}
+	return value.String(), nil
+}
+
+// configEscapes are the single-character escapes of TOML basic strings.
+var configEscapes = map[byte]byte{
+	'b': '\b', 't': '\t', 'n': '\n', 'f': '\f', 'r': '\r', 'e': 0x1b, '"': '"', '\\': '\\',
+}
+
+// configHexEscapes are the code point escapes and their number of hex digits.
+var configHexEscapes = map[byte]int{'x': 2, 'u': 4, 'U': 8}
+
+// checkControlCharacters rejects the control characters TOML does not allow
+// inside strings; tab is the only one permitted.
+func checkControlCharacters(body string) *configProblem {
+	for _, r := range body {
+		if (r < 0x20 && r != '\t') || r == 0x7f {
+			return &configProblem{Message: "config_control_character", Args: []any{string(r)}}
+		}
+	}
+	return nil
+}
+
+func onlyComment(rest string) *configProblem {
+	rest = strings.TrimSpace(rest)
+	if rest != "" && !strings.HasPrefix(rest, "#") {
+		return &configProblem{Message: "config_unexpected_text", Args: []any{rest}}
+	}
+	return nil
+}
@@ -0,0 +1,284 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseConfigFile(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		content string
+		want    map[string]configFileValue
+		wantErr string
+	}{
+		{
+			name: "tables and value types",
+			content: `# steamid-service
+[server]
+host = "127.0.0.1"  # loopback only
+port = 8080
+shutdown_timeout = '5s'
+
+[batch]
+max_items = 1_000
+
+[log]
+debug = true
+`,
+			want: map[string]configFileValue{
+				"server.host":             {Value: "127.0.0.1", Line: 3},
+				"server.port":             {Value: "8080", Line: 4},
+				"server.shutdown_timeout": {Value: "5s", Line: 5},
+				"batch.max_items":         {Value: "1000", Line: 8},
+				"log.debug":               {Value: "true", Line: 11},
+			},
+		},
+		{
+			name:    "escaped quote",
+			content: "[cors]\nallow_origin = \"https://a.example/\\\"x\\\"\" # comment",
+			want:    map[string]configFileValue{"cors.allow_origin": {Value: `https://a.example/"x"`, Line: 2}},
+		},
+		{
+			name:    "literal string keeps backslashes",
+			content: "[batch]\njob_store_dir = 'C:\\jobs\\new' # windows",
+			want:    map[string]configFileValue{"batch.job_store_dir": {Value: `C:\jobs\new`, Line: 2}},
+		},
+		{
+			name:    "literal string with a double quote",
+			content: `[cors]` + "\n" + `allow_origin = 'a"b'`,
+			want:    map[string]configFileValue{"cors.allow_origin": {Value: `a"b`, Line: 2}},
+		},
+		{
+			name:    "basic string escapes",
+			content: `[batch]` + "\n" + `job_store_dir = "C:\\jobs\t\e\x41\u00e9\U0001F600"`,
+			want:    map[string]configFileValue{"batch.job_store_dir": {Value: "C:\\jobs\t\x1bA\u00e9\U0001F600", Line: 2}},
+		},
+		{name: "unknown escape", content: `[batch]` + "\n" + `job_store_dir = "C:\path"`, wantErr: `test.toml:2: batch.job_store_dir: invalid escape "\\p" in string`},
+		{name: "short unicode escape", content: `[log]` + "\n" + `level = "\u00e"`, wantErr: `test.toml:2: log.level: invalid escape "\\u00e" in string`},
+		{name: "surrogate escape", content: `[log]` + "\n" + `level = "\uD800"`, wantErr: `test.toml:2: log.level: invalid escape "\\uD800" in string`},
+		{name: "unterminated literal string", content: "[log]\nlevel = 'debug", wantErr: "test.toml:2: log.level: unterminated string"},
+		{name: "control character", content: "[log]\nlevel = 'de\x01bug'", wantErr: `test.toml:2: log.level: control character "\x01" in string`},
+		{name: "unknown key", content: "[server]\nhots = \"x\"", wantErr: `test.toml:2: unknown setting "server.hots"`},
+		{name: "key outside its table", content: "port = 80", wantErr: `test.toml:1: unknown setting "port"`},
+		{name: "set twice", content: "[server]\nport = 80\nport = 81", wantErr: "test.toml:3: server.port: already set on line 2"},
+		{name: "bare word", content: "[log]\nlevel = debug", wantErr: `test.toml:2: log.level: unsupported value "debug"`},
+		{name: "double underscore", content: "[batch]\nmax_items = 1__2", wantErr: `test.toml:2: batch.max_items: unsupported value "1__2"`},
+		{name: "trailing underscore", content: "[batch]\nmax_items = 12_", wantErr: `test.toml:2: batch.max_items: unsupported value "12_"`},
+		{name: "leading underscore", content: "[batch]\nmax_items = _12", wantErr: `test.toml:2: batch.max_items: unsupported value "_12"`},
+		{name: "unterminated string", content: "[log]\nlevel = \"debug", wantErr: "test.toml:2: log.level: unterminated string"},
+		{name: "text after string", content: "[log]\nlevel = \"debug\" x", wantErr: `test.toml:2: log.level: unexpected "x" after value`},
+		{name: "not a pair", content: "[server]\nport", wantErr: "test.toml:2: expected [section] or key = value"},
+		{
+			name:    "every bad line",
+			content: "[server]\nhots = 1\nport\n[log]\nlevel = debug",
+			wantErr: `test.toml:2: unknown setting "server.hots"; test.toml:3: expected [section] or key = value; test.toml:5: log.level: unsupported value "debug"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseConfigFile("test.toml", strings.NewReader(tc.content))
+			if tc.wantErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tc.wantErr) {
+					t.Fatalf("expected error %q, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil || !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("expected %v, got %v (err %v)", tc.want, got, err)
+			}
+		})
+	}
+}
+
+func TestLoadConfigPrecedence(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "steamid-service.toml")
+	content := "[batch]\nmax_items = 10\njob_ttl = \"1m\"\n\n[log]\nlevel = \"warn\"\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	testCases := []struct {
+		name      string
+		args      []string
+		env       map[string]string
+		wantItems int
+		wantTTL   time.Duration
+		wantLevel string
+		wantErr   string
+	}{
+		{name: "defaults", wantItems: 32, wantTTL: 10 * time.Minute},
+		{name: "file", args: []string{"-config", path}, wantItems: 10, wantTTL: time.Minute, wantLevel: "warn"},
+		{name: "file from env", env: map[string]string{"CONFIG_FILE": path}, wantItems: 10, wantTTL: time.Minute, wantLevel: "warn"},
+		{
+			name:      "env over file",
+			args:      []string{"-config", path},
+			env:       map[string]string{"MAX_BATCH_ITEMS": "20", "LOG_LEVEL": "error"},
+			wantItems: 20, wantTTL: time.Minute, wantLevel: "error",
+		},
+		{
+			name:      "flag over env",
+			args:      []string{"-config", path, "-max-batch-items", "30", "-job-ttl=2m"},
+			env:       map[string]string{"MAX_BATCH_ITEMS": "20"},
+			wantItems: 30, wantTTL: 2 * time.Minute, wantLevel: "warn",
+		},
+		{name: "invalid flag", args: []string{"-max-batch-items", "0"}, wantErr: `-max-batch-items: batch.max_items: "0" is not a positive integer`},
+		{name: "invalid log level", args: []string{"-log-level", "loud"}, wantErr: `-log-level: log.level: "loud" is not one of debug, info, warn, error`},
+		{name: "invalid env", env: map[string]string{"MAX_BATCH_ITEMS": "lots"}, wantErr: `MAX_BATCH_ITEMS: batch.max_items: "lots" is not a positive integer`},
+		{name: "missing file", args: []string{"-config", path + ".missing"}, wantErr: "open " + path + ".missing"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg, err := loadConfig(tc.args, func(key string) string { return tc.env[key] })
+			if tc.wantErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tc.wantErr) {
+					t.Fatalf("expected error %q, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadConfig: %v", err)
+			}
+			if cfg.MaxBatchItems != tc.wantItems || cfg.JobTTL != tc.wantTTL || cfg.LogLevel != tc.wantLevel {
+				t.Fatalf("unexpected config items=%d ttl=%s level=%q", cfg.MaxBatchItems, cfg.JobTTL, cfg.LogLevel)
+			}
+		})
+	}
+}
+
+func TestReloadConfigOnlyAppliesReloadableSettings(t *testing.T) {
+	previous := appCfg
+	t.Cleanup(func() {
+		appCfg = previous
+		loadBackendMessages(appCfg.BackendLang)
+	})
+
+	next := appCfg
+	next.MaxBatchItems = appCfg.MaxBatchItems + 1
+	next.BackendLang = "es"
+	next.Port = "8081"
+
+	changed, restart := reloadConfig(next)
+	if !reflect.DeepEqual(changed, []string{"batch.max_items", "i18n.backend_lang"}) || !reflect.DeepEqual(restart, []string{"server.port"}) {
+		t.Fatalf("unexpected changed=%v restart=%v", changed, restart)
+	}
+	if maxItems, _, _ := batchLimits(); maxItems != next.MaxBatchItems || appCfg.Port != previous.Port {
+		t.Fatalf("unexpected config after reload: max_items=%d port=%s", maxItems, appCfg.Port)
+	}
+	if got := msgBackend("config_failed"); !strings.Contains(got, "inválida") {
+		t.Fatalf("backend messages were not reloaded: %q", got)
+	}
+}
+
+func TestLoadConfigRejectsInvalidValues(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		env     map[string]string
+		wantErr string
+	}{
+		{name: "universe", env: map[string]string{"SID2_UNIVERSE": "7"}, wantErr: `SID2_UNIVERSE: steamid.sid2_universe: "7" is not one of 0, 1`},
+		{name: "language", env: map[string]string{"BACKEND_LANG": "fr"}, wantErr: `BACKEND_LANG: i18n.backend_lang: "fr" is not one of en, es`},
+		{name: "port", env: map[string]string{"PORT": "70000"}, wantErr: `PORT: server.port: "70000" is not a port between 1 and 65535`},
+		{name: "metrics address", env: map[string]string{"METRICS_ADDR": "9090"}, wantErr: `METRICS_ADDR: server.metrics_addr: "9090" is not a host:port address`},
+		{name: "negative delay", env: map[string]string{"SHUTDOWN_DELAY": "-1s"}, wantErr: `SHUTDOWN_DELAY: server.shutdown_delay: "-1s" is not a duration of 0s or more`},
+		{name: "boolean", env: map[string]string{"DEBUG": "yes"}, wantErr: `DEBUG: log.debug: "yes" is not a boolean`},
+		{name: "collector url", env: map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "localhost:4318"}, wantErr: `OTEL_EXPORTER_OTLP_ENDPOINT: tracing.otlp_endpoint: "localhost:4318" is not an http or https URL`},
+		{name: "cors origin with path", env: map[string]string{"CORS_ALLOW_ORIGIN": "https://example.com/app"}, wantErr: `CORS_ALLOW_ORIGIN: cors.allow_origin: "https://example.com/app" is not *`},
+		{
+			name:    "every problem",
+			env:     map[string]string{"SID2_UNIVERSE": "7", "MAX_BATCH_ITEMS": "x"},
+			wantErr: `SID2_UNIVERSE: steamid.sid2_universe: "7" is not one of 0, 1; MAX_BATCH_ITEMS: batch.max_items: "x" is not a positive integer`,
+		},
+		{name: "valid", env: map[string]string{"CORS_ALLOW_ORIGIN": "https://example.com", "METRICS_ADDR": ":9090", "SHUTDOWN_DELAY": "0s"}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := loadConfig(nil, func(key string) string { return tc.env[key] })
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("loadConfig: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.HasPrefix(err.Error(), tc.wantErr) {
+				t.Fatalf("expected error %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLoadConfigRecordsSources(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "steamid-service.toml")
+	if err := os.WriteFile(path, []byte("[batch]\nmax_items = 10\n\n[server]\nport = 8080\n"), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	env := map[string]string{"PORT": "8081"}
+	cfg, err := loadConfig([]string{"-config", path, "-backend-lang", "es"}, func(key string) string { return env[key] })
+	if err != nil {
+		t.Fatalf("loadConfig: %v", err)
+	}
+
+	want := map[string]configSource{
+		"server.host":       {Name: "default"},
+		"server.port":       {Name: "env", Origin: "PORT"},
+		"batch.max_items":   {Name: "file", Origin: path + ":2"},
+		"i18n.backend_lang": {Name: "flag", Origin: "-backend-lang"},
+	}
+	for key, source := range want {
+		if cfg.Sources[key] != source {
+			t.Fatalf("expected source %+v for %s, got %+v", source, key, cfg.Sources[key])
+		}
+	}
+}
+
+func TestRunCheckConfigCommand(t *testing.T) {
+	t.Cleanup(func() {
+		loadBackendMessages(appCfg.BackendLang)
+	})
+
+	var output strings.Builder
+	env := map[string]string{"BACKEND_LANG": "es"}
+	if err := runCheckConfigCommand([]string{"-port", "8080"}, func(key string) string { return env[key] }, &output); err != nil {
+		t.Fatalf("check-config: %v", err)
+	}
+	for _, want := range []string{"OPCIÓN", "server.port                   8080", "flag (-port)", "env (BACKEND_LANG)", "✅ Configuración válida"} {
+		if !strings.Contains(output.String(), want) {
+			t.Fatalf("expected %q in output:\n%s", want, output.String())
+		}
+	}
+
+	output.Reset()
+	env["SID2_UNIVERSE"] = "7"
+	err := runCheckConfigCommand(nil, func(key string) string { return env[key] }, &output)
+	if err == nil || err.Error() != "1 opción(es) inválida(s)" {
+		t.Fatalf("expected one invalid setting, got %v", err)
+	}
+	if !strings.Contains(output.String(), `❌ SID2_UNIVERSE: steamid.sid2_universe: "7" no es uno de 0, 1`) {
+		t.Fatalf("expected the localized problem in output:\n%s", output.String())
+	}
+}
@@ -1,157 +1,198 @@
 package app
 
 import (
-	"strconv"
-	"strings"
+	"errors"
+
+	"steamid-service/pkg/steamid"
 )
 
-func isValidAccountID(accountID uint64) bool {
-	return accountID > 0 && accountID <= MaxAccountID
+// steamIDErrorFrom maps a pkg/steamid error onto the service error with the same key.
+func steamIDErrorFrom(err error) SteamIDError {
+	if err == nil {
+		return ErrorNone
+	}
+	var libErr steamid.Error
+	if errors.As(err, &libErr) {
+		return SteamIDError(libErr)
+	}
+	return ErrorConversionFailed
 }
 
-func isASCIIUnsignedDecimal(value string) bool {
-	for i := 0; i < len(value); i++ {
-		if value[i] < '0' || value[i] > '9' {
-			return false
-		}
+func accountIDResult(id steamid.SteamID, err error) ConversionResult {
+	if err != nil {
+		return ConversionResult{"", steamIDErrorFrom(err)}
 	}
-
-	return true
+	return ConversionResult{steamid.FormatAccountID(id), ErrorNone}
 }
 
-func AIDFromSID64(steamid64Str string) ConversionResult {
-	if len(steamid64Str) == 0 {
-		return ConversionResult{"", ErrorInvalidLength}
-	}
-	if len(steamid64Str) != 17 {
-		return ConversionResult{"", ErrorInvalidLength}
-	}
-	if !isASCIIUnsignedDecimal(steamid64Str) {
-		return ConversionResult{"", ErrorInvalidCharacters}
-	}
-	steamid64, err := strconv.ParseUint(steamid64Str, 10, 64)
+func steamID64Result(id steamid.SteamID, err error) ConversionResult {
 	if err != nil {
-		return ConversionResult{"", ErrorInvalidSteamID64}
-	}
-	if steamid64 <= STEAMID64_BASE || steamid64 > MaxSteamID64 {
-		return ConversionResult{"", ErrorInvalidSteamID64}
-	}
-	accountID := steamid64 - STEAMID64_BASE
-	if !isValidAccountID(accountID) {
-		return ConversionResult{"", ErrorInvalidSteamID64}
+		return ConversionResult{"", steamIDErrorFrom(err)}
 	}
-	return ConversionResult{strconv.FormatUint(accountID, 10), ErrorNone}
+	return ConversionResult{id.SteamID64(), ErrorNone}
 }
 
-func SID64FromAID(accountIDStr string) ConversionResult {
-	if len(accountIDStr) == 0 {
-		return ConversionResult{"", ErrorInvalidLength}
-	}
-	if !isASCIIUnsignedDecimal(accountIDStr) {
-		return ConversionResult{"", ErrorInvalidCharacters}
-	}
-	accountID, err := strconv.ParseUint(accountIDStr, 10, 64)
+func formattedResult(value string, err error) ConversionResult {
 	if err != nil {
-		return ConversionResult{"", ErrorInvalidAccountID}
+		return ConversionResult{"", steamIDErrorFrom(err)}
 	}
-	if !isValidAccountID(accountID) {
-		return ConversionResult{"", ErrorInvalidAccountID}
-	}
-	steamid64 := accountID + STEAMID64_BASE
-	return ConversionResult{strconv.FormatUint(steamid64, 10), ErrorNone}
+	return ConversionResult{value, ErrorNone}
 }
 
-func AIDFromSID2(steamid2 string) ConversionResult {
-	if len(steamid2) == 0 {
-		return ConversionResult{"", ErrorInvalidLength}
-	}
-	if len(steamid2) < 11 {
-		return ConversionResult{"", ErrorInvalidLength}
-	}
-	if !strings.HasPrefix(steamid2, "STEAM_") {
-		return ConversionResult{"", ErrorInvalidSteamID2}
-	}
-	parts := strings.Split(steamid2, ":")
-	if len(parts) != 3 {
-		return ConversionResult{"", ErrorInvalidSteamID2}
-	}
-	universe, err := strconv.ParseUint(parts[0][6:], 10, 64)
-	if err != nil || (universe != 0 && universe != 1) {
-		return ConversionResult{"", ErrorInvalidSteamID2}
-	}
-	y, err := strconv.ParseUint(parts[1], 10, 64)
-	if err != nil || (y != 0 && y != 1) {
-		return ConversionResult{"", ErrorInvalidSteamID2}
-	}
-	z, err := strconv.ParseUint(parts[2], 10, 64)
+func individualFromAccountID(accountIDStr string) (steamid.SteamID, error) {
+	accountID, err := steamid.ParseAccountID(accountIDStr)
 	if err != nil {
-		return ConversionResult{"", ErrorInvalidSteamID2}
+		return steamid.SteamID{}, err
 	}
-	if z > (MaxAccountID-y)/2 {
-		return ConversionResult{"", ErrorInvalidSteamID2}
+	return steamid.NewIndividual(steamid.UniversePublic, accountID), nil
+}
+
+func clanFromAccountID(accountIDStr string) (steamid.SteamID, error) {
+	accountID, err := steamid.ParseAccountID(accountIDStr)
+	if err != nil {
+		return steamid.SteamID{}, err
 	}
-	accountID := z*2 + y
-	if !isValidAccountID(accountID) {
-		return ConversionResult{"", ErrorInvalidSteamID2}
+	return steamid.NewClan(steamid.UniversePublic, accountID), nil
+}
+
+func isValidSID2Universe(universe string) bool {
+	_, err := steamid.ParseSteamID2Universe(universe)
+	return err == nil
+}
+
+// formatSteamID2 renders id with the configured "0"/"1" digit for public accounts.
+func formatSteamID2(id steamid.SteamID, universe string) (string, error) {
+	publicUniverse, err := steamid.ParseSteamID2Universe(universe)
+	if err != nil {
+		return "", err
 	}
-	return ConversionResult{strconv.FormatUint(accountID, 10), ErrorNone}
+	return steamid.FormatSteamID2(id, publicUniverse)
+}
+
+func AIDFromSID64(steamid64Str string) ConversionResult {
+	return accountIDResult(steamid.ParseSteamID64(steamid64Str))
+}
+
+func SID64FromAID(accountIDStr string) ConversionResult {
+	return steamID64Result(individualFromAccountID(accountIDStr))
+}
+
+func AIDFromSID2(steamid2 string) ConversionResult {
+	return accountIDResult(steamid.ParseSteamID2(steamid2))
 }
 
 func AIDFromSID3(steamid3 string) ConversionResult {
-	if len(steamid3) == 0 {
-		return ConversionResult{"", ErrorInvalidLength}
-	}
-	if len(steamid3) < 8 {
-		return ConversionResult{"", ErrorInvalidLength}
-	}
-	if !strings.HasPrefix(steamid3, "[U:1:") || !strings.HasSuffix(steamid3, "]") {
-		return ConversionResult{"", ErrorInvalidSteamID3}
-	}
-	accountIDStr := steamid3[5 : len(steamid3)-1]
-	if !isASCIIUnsignedDecimal(accountIDStr) {
-		return ConversionResult{"", ErrorInvalidCharacters}
-	}
-	accountID, err := strconv.ParseUint(accountIDStr, 10, 64)
-	if err != nil || !isValidAccountID(accountID) {
-		return ConversionResult{"", ErrorInvalidSteamID3}
-	}
-	return ConversionResult{accountIDStr, ErrorNone}
+	return accountIDResult(steamid.ParseSteamID3(steamid3))
 }
 
 func SID2FromAID(accountIDStr string) ConversionResult {
-	if len(accountIDStr) == 0 {
-		return ConversionResult{"", ErrorInvalidLength}
+	return SID2FromAIDWithUniverse(accountIDStr, appCfg.SID2Universe)
+}
+
+func SID2FromAIDWithUniverse(accountIDStr, universe string) ConversionResult {
+	id, err := individualFromAccountID(accountIDStr)
+	if err != nil {
+		return ConversionResult{"", steamIDErrorFrom(err)}
 	}
-	if !isASCIIUnsignedDecimal(accountIDStr) {
-		return ConversionResult{"", ErrorInvalidCharacters}
+	return formattedResult(formatSteamID2(id, universe))
+}
+
+func SID3FromAID(accountIDStr string) ConversionResult {
+	id, err := individualFromAccountID(accountIDStr)
+	if err != nil {
+		return ConversionResult{"", steamIDErrorFrom(err)}
 	}
-	accountID, err := strconv.ParseUint(accountIDStr, 10, 64)
+	return formattedResult(steamid.FormatSteamID3(id))
+}
+
+func SID2FromSID64(steamid64Str string) ConversionResult {
+	return SID2FromSID64WithUniverse(steamid64Str, appCfg.SID2Universe)
+}
+
+func SID2FromSID64WithUniverse(steamid64Str, universe string) ConversionResult {
+	id, err := steamid.ParseSteamID64(steamid64Str)
 	if err != nil {
-		return ConversionResult{"", ErrorInvalidAccountID}
+		return ConversionResult{"", steamIDErrorFrom(err)}
 	}
-	if !isValidAccountID(accountID) {
-		return ConversionResult{"", ErrorInvalidAccountID}
+	return formattedResult(formatSteamID2(id, universe))
+}
+
+func SID3FromSID64(steamid64Str string) ConversionResult {
+	id, err := steamid.ParseSteamID64(steamid64Str)
+	if err != nil {
+		return ConversionResult{"", steamIDErrorFrom(err)}
 	}
-	y := accountID & 1
-	z := accountID >> 1
-	steamid2 := "STEAM_" + appCfg.SID2Universe + ":" + strconv.FormatUint(y, 10) + ":" + strconv.FormatUint(z, 10)
-	return ConversionResult{steamid2, ErrorNone}
+	return formattedResult(steamid.FormatSteamID3(id))
 }
 
-func SID3FromAID(accountIDStr string) ConversionResult {
-	if len(accountIDStr) == 0 {
-		return ConversionResult{"", ErrorInvalidLength}
+func SID64FromSID2(steamid2 string) ConversionResult {
+	return steamID64Result(steamid.ParseSteamID2(steamid2))
+}
+
+func SID64FromSID3(steamid3 string) ConversionResult {
+	return steamID64Result(steamid.ParseSteamID3(steamid3))
+}
+
+func ClanIDFromGID64(groupid64Str string) ConversionResult {
+	return accountIDResult(steamid.ParseGroupSteamID64(groupid64Str))
+}
+
+func ClanIDFromGID3(groupid3 string) ConversionResult {
+	return accountIDResult(steamid.ParseGroupSteamID3(groupid3))
+}
+
+func GID64FromClanID(clanIDStr string) ConversionResult {
+	return steamID64Result(clanFromAccountID(clanIDStr))
+}
+
+func GID64FromGID3(groupid3 string) ConversionResult {
+	return steamID64Result(steamid.ParseGroupSteamID3(groupid3))
+}
+
+func GID3FromClanID(clanIDStr string) ConversionResult {
+	id, err := clanFromAccountID(clanIDStr)
+	if err != nil {
+		return ConversionResult{"", steamIDErrorFrom(err)}
 	}
-	if !isASCIIUnsignedDecimal(accountIDStr) {
-		return ConversionResult{"", ErrorInvalidCharacters}
+	return formattedResult(steamid.FormatSteamID3(id))
+}
+
+func GID3FromGID64(groupid64Str string) ConversionResult {
+	id, err := steamid.ParseGroupSteamID64(groupid64Str)
+	if err != nil {
+		return ConversionResult{"", steamIDErrorFrom(err)}
 	}
-	accountID, err := strconv.ParseUint(accountIDStr, 10, 64)
+	return formattedResult(steamid.FormatSteamID3(id))
+}
+
+func SID64FromSID64(steamid64Str string) ConversionResult {
+	return steamID64Result(steamid.ParseSteamID64(steamid64Str))
+}
+
+func SID64FromHex(steamidHex string) ConversionResult {
+	return steamID64Result(steamid.ParseHex(steamidHex))
+}
+
+func SID64FromProfileURL(profileURL string) ConversionResult {
+	return steamID64Result(steamid.ParseProfileURL(profileURL))
+}
+
+func HexFromSID64(steamid64Str string) ConversionResult {
+	id, err := steamid.ParseSteamID64(steamid64Str)
 	if err != nil {
-		return ConversionResult{"", ErrorInvalidAccountID}
+		return ConversionResult{"", steamIDErrorFrom(err)}
 	}
-	if !isValidAccountID(accountID) {
-		return ConversionResult{"", ErrorInvalidAccountID}
+	return ConversionResult{steamid.FormatHex(id), ErrorNone}
+}
+
+func ProfileURLFromSID64(steamid64Str string) ConversionResult {
+	id, err := steamid.ParseSteamID64(steamid64Str)
+	if err != nil {
+		return ConversionResult{"", steamIDErrorFrom(err)}
 	}
-	steamid3 := "[U:1:" + strconv.FormatUint(accountID, 10) + "]"
-	return ConversionResult{steamid3, ErrorNone}
+	return formattedResult(steamid.FormatProfileURL(id))
+}
+
+func GID64FromGID64(groupid64Str string) ConversionResult {
+	return steamID64Result(steamid.ParseGroupSteamID64(groupid64Str))
 }
@@ -3,6 +3,8 @@ package app
 import (
 	"fmt"
 	"testing"
+
+	"steamid-service/pkg/steamid"
 )
 
 func TestAIDFromSID64RejectsOutOfRangeValues(t *testing.T) {
@@ -123,3 +125,85 @@ func TestAIDFromSID2RejectsInvalidAccountRange(t *testing.T) {
 		})
 	}
 }
+
+func TestClanConversionsRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		convert func(string) ConversionResult
+		input   string
+		wantErr SteamIDError
+		wantVal string
+	}{
+		{name: "gid64 to clan id", convert: ClanIDFromGID64, input: "103582791429521412", wantVal: "4"},
+		{name: "gid64 to gid3", convert: GID3FromGID64, input: "103582791429521412", wantVal: "[g:1:4]"},
+		{name: "clan id to gid64", convert: GID64FromClanID, input: "4", wantVal: "103582791429521412"},
+		{name: "clan id to gid3", convert: GID3FromClanID, input: "4", wantVal: "[g:1:4]"},
+		{name: "gid3 to gid64", convert: GID64FromGID3, input: "[g:1:4]", wantVal: "103582791429521412"},
+		{name: "gid3 to clan id", convert: ClanIDFromGID3, input: "[g:1:4]", wantVal: "4"},
+		{name: "individual steamid64 is not a group", convert: ClanIDFromGID64, input: "76561197960287930", wantErr: ErrorInvalidGroupID},
+		{name: "individual steamid3 is not a group", convert: GID64FromGID3, input: "[U:1:22202]", wantErr: ErrorInvalidGroupID},
+		{name: "zero clan id", convert: GID64FromClanID, input: "0", wantErr: ErrorInvalidAccountID},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			wantErr := tc.wantErr
+			if wantErr == "" {
+				wantErr = ErrorNone
+			}
+
+			got := tc.convert(tc.input)
+			if got.Error != wantErr {
+				t.Fatalf("expected error %q, got %q", wantErr, got.Error)
+			}
+			if got.Value != tc.wantVal {
+				t.Fatalf("expected value %q, got %q", tc.wantVal, got.Value)
+			}
+		})
+	}
+}
+
+func TestSID2FromSID64HandlesUniverseAndType(t *testing.T) {
+	t.Parallel()
+
+	if got := SID2FromSID64("148618791998215866"); got.Value != "STEAM_2:0:11101" || got.Error != ErrorNone {
+		t.Fatalf("unexpected beta universe result %+v", got)
+	}
+	if got := SID2FromSID64("103582791429521412"); got.Error != ErrorUnsupportedAccountType {
+		t.Fatalf("expected unsupported account type for clan, got %+v", got)
+	}
+	if got := SID64FromSID2("STEAM_2:0:11101"); got.Value != "148618791998215866" || got.Error != ErrorNone {
+		t.Fatalf("unexpected beta universe SteamID64 %+v", got)
+	}
+	if got := SID64FromSID2("STEAM_0:0:11101"); got.Value != "76561197960287930" || got.Error != ErrorNone {
+		t.Fatalf("expected STEAM_0 to map to the public universe, got %+v", got)
+	}
+}
+
+func TestServiceErrorsShareLibraryKeys(t *testing.T) {
+	t.Parallel()
+
+	libErrors := []steamid.Error{
+		steamid.ErrInvalidFormat,
+		steamid.ErrInvalidLength,
+		steamid.ErrInvalidCharacters,
+		steamid.ErrInvalidSteamID2,
+		steamid.ErrInvalidSteamID3,
+		steamid.ErrInvalidSteamID64,
+		steamid.ErrInvalidAccountID,
+		steamid.ErrInvalidGroupID,
+		steamid.ErrInvalidUniverse,
+		steamid.ErrUnsupportedAccountType,
+	}
+
+	for _, libErr := range libErrors {
+		if _, ok := errorMessages[steamIDErrorFrom(libErr)]; !ok {
+			t.Fatalf("library error %q has no service error message", libErr)
+		}
+	}
+}
@@ -0,0 +1,357 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	csvModeAppend  = "append"
+	csvModeReplace = "replace"
+)
+
+// csvOptions selects the columns to convert and how results are written back.
+// Columns are header names (case-insensitive) or 1-based indexes.
+type csvOptions struct {
+	Columns   []string
+	From      string
+	To        string
+	Mode      string
+	Header    bool
+	Delimiter rune
+	Convert   conversionOptions
+}
+
+type csvColumn struct {
+	Index int
+	Name  string
+}
+
+// csvConverter rewrites CSV records one at a time. For every selected column it
+// appends "<column>_<to>" (append mode) or rewrites the cell in place (replace
+// mode), and always appends "<column>_error" with the SteamIDError code of a
+// failed cell, so one bad row never aborts the file.
+type csvConverter struct {
+	reader  *csv.Reader
+	options csvOptions
+	config  conversionHandlerConfig
+	lang    string
+	columns []csvColumn
+	header  []string
+	width   int
+	pending []string
+}
+
+func splitCSVColumns(value string) []string {
+	var columns []string
+	for _, column := range strings.Split(value, ",") {
+		if column = strings.TrimSpace(column); column != "" {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+func parseCSVDelimiter(value string) (rune, bool) {
+	if value == "" {
+		return ',', true
+	}
+	if value == `\t` || value == "tab" {
+		return '\t', true
+	}
+	delimiter, size := utf8.DecodeRuneInString(value)
+	if size != len(value) || delimiter == '"' || delimiter == '\r' || delimiter == '\n' || delimiter == utf8.RuneError {
+		return 0, false
+	}
+	return delimiter, true
+}
+
+// newCSVConverter validates the options and reads the header (or the first data
+// row when Header is false) so that column errors surface before any output.
+func newCSVConverter(input io.Reader, options csvOptions, lang string) (*csvConverter, SteamIDError, string) {
+	if len(options.Columns) == 0 {
+		return nil, ErrorMissingParameter, msg("csv_columns_required", lang)
+	}
+	if options.Mode == "" {
+		options.Mode = csvModeAppend
+	}
+	if options.Mode != csvModeAppend && options.Mode != csvModeReplace {
+		return nil, ErrorInvalidFormat, msgf("csv_mode_invalid", lang, options.Mode)
+	}
+	if options.From == "" {
+		options.From = string(steamIDFormatAuto)
+	}
+
+	config, pairErr, message := resolveConversionPair(options.From, options.To, lang)
+	if !pairErr.IsValid() {
+		return nil, pairErr, message
+	}
+
+	reader := csv.NewReader(input)
+	reader.Comma = options.Delimiter
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.ReuseRecord = false
+
+	first, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, ErrorMissingParameter, msg("csv_empty", lang)
+	}
+	if err != nil {
+		return nil, ErrorInvalidFormat, msg("batch_parse_failed", lang)
+	}
+
+	converter := &csvConverter{
+		reader:  reader,
+		options: options,
+		config:  config,
+		lang:    lang,
+		width:   len(first),
+	}
+	if options.Header {
+		converter.header = first
+	} else {
+		converter.pending = first
+	}
+
+	for _, name := range options.Columns {
+		column, ok := converter.resolveColumn(name)
+		if !ok {
+			return nil, ErrorInvalidFormat, msgf("csv_column_unknown", lang, name)
+		}
+		converter.columns = append(converter.columns, column)
+	}
+
+	return converter, ErrorNone, ""
+}
+
+func (c *csvConverter) resolveColumn(name string) (csvColumn, bool) {
+	if index, err := strconv.Atoi(name); err == nil {
+		if index < 1 || index > c.width {
+			return csvColumn{}, false
+		}
+		column := csvColumn{Index: index - 1, Name: "column" + name}
+		if c.header != nil {
+			column.Name = strings.TrimSpace(c.header[index-1])
+		}
+		return column, true
+	}
+
+	for index, field := range c.header {
+		if strings.EqualFold(strings.TrimSpace(field), name) {
+			return csvColumn{Index: index, Name: strings.TrimSpace(field)}, true
+		}
+	}
+	return csvColumn{}, false
+}
+
+// writeTo writes the rewritten CSV to output, calling afterRow after each record.
+// It returns the number of data rows written.
+func (c *csvConverter) writeTo(ctx context.Context, output io.Writer, afterRow func()) (int, error) {
+	writer := csv.NewWriter(output)
+	writer.Comma = c.options.Delimiter
+
+	if c.header != nil {
+		if err := writer.Write(c.rewriteHeader()); err != nil {
+			return 0, err
+		}
+	}
+
+	rows := 0
+	for {
+		record := c.pending
+		c.pending = nil
+		if record == nil {
+			var err error
+			record, err = c.reader.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				writer.Flush()
+				return rows, err
+			}
+		}
+
+		if err := writer.Write(c.rewriteRecord(ctx, record)); err != nil {
+			return rows, err
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return rows, err
+		}
+		rows++
+		if afterRow != nil {
+			afterRow()
+		}
+	}
+
+	writer.Flush()
+	return rows, writer.Error()
+}
+
+func (c *csvConverter) rewriteHeader() []string {
+	header := append([]string(nil), c.header...)
+	for _, column := range c.columns {
+		if c.options.Mode == csvModeAppend {
+			header = append(header, column.Name+"_"+strings.ToLower(c.options.To))
+		}
+		header = append(header, column.Name+"_error")
+	}
+	return header
+}
+
+func (c *csvConverter) rewriteRecord(ctx context.Context, record []string) []string {
+	width := c.width
+	if len(record) > width {
+		width = len(record)
+	}
+	out := make([]string, width, width+2*len(c.columns))
+	copy(out, record)
+
+	for _, column := range c.columns {
+		value, code := c.convertCell(ctx, record, column.Index)
+		switch c.options.Mode {
+		case csvModeAppend:
+			out = append(out, value)
+		case csvModeReplace:
+			if code == "" {
+				out[column.Index] = value
+			}
+		}
+		out = append(out, code)
+	}
+	return out
+}
+
+func (c *csvConverter) convertCell(ctx context.Context, record []string, index int) (string, string) {
+	if index >= len(record) || strings.TrimSpace(record[index]) == "" {
+		return "", ErrorMissingParameter.Key()
+	}
+
+	_, result := c.config.convertInput(ctx, strings.TrimSpace(record[index]), c.lang, c.options.Convert)
+	if !result.Error.IsValid() {
+		return "", result.Error.Key()
+	}
+	return result.Value, ""
+}
+
+func handleCSV(w http.ResponseWriter, r *http.Request) {
+	lang := getLang(r)
+	logDebug(r, "csv request: %v", r.URL.RawQuery)
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, r, ErrorMissingParameter, msg("csv_body_required", lang), "csv request without POST body")
+		return
+	}
+
+	query := r.URL.Query()
+	to := query.Get("to")
+	if to == "" {
+		writeErrorResponse(w, r, ErrorMissingParameter, msg("target_param_required", lang), "to query parameter missing")
+		return
+	}
+	delimiter, ok := parseCSVDelimiter(query.Get("delimiter"))
+	if !ok {
+		writeErrorResponse(w, r, ErrorInvalidFormat, msgf("csv_delimiter_invalid", lang, query.Get("delimiter")), "csv delimiter rejected")
+		return
+	}
+
+	converter, convErr, message := newCSVConverter(r.Body, csvOptions{
+		Columns:   splitCSVColumns(query.Get("columns")),
+		From:      query.Get("from"),
+		To:        to,
+		Mode:      query.Get("mode"),
+		Header:    query.Get("header") != "0",
+		Delimiter: delimiter,
+	}, lang)
+	if !convErr.IsValid() {
+		writeErrorResponse(w, r, convErr, message, "csv input rejected")
+		return
+	}
+
+	opts, optsErr := resolveConversionOptions(r, converter.config)
+	if !optsErr.IsValid() {
+		writeErrorResponse(w, r, optsErr, "", "sid2 universe override rejected")
+		return
+	}
+	converter.options.Convert = opts
+
+	flusher := newStreamFlusher(w)
+	defer flusher.stop()
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	setCORSHeader(w)
+	w.WriteHeader(http.StatusOK)
+
+	ctx, span := startItemsSpan(r.Context(), "csv")
+	defer span.finish()
+	span.setAttributes(stringAttribute("steamidtools.conversion", converter.config.BatchLabel))
+
+	rows, err := converter.writeTo(ctx, flusher, flusher.written)
+	span.setAttributes(intAttribute("steamidtools.batch.items", rows))
+	flusher.flush()
+	if err != nil {
+		appWarnf("csv conversion aborted: conversion=%s rows=%d error=%v remote_addr=%s", converter.config.BatchLabel, rows, err, r.RemoteAddr)
+		return
+	}
+	appInfof("csv conversion processed: conversion=%s rows=%d remote_addr=%s", converter.config.BatchLabel, rows, r.RemoteAddr)
+}
+
+// runCSVCommand implements `steamid-service csv`: it reads CSV from stdin and
+// writes the rewritten CSV to stdout using the same rules as the /csv endpoint.
+func runCSVCommand(args []string, stdin io.Reader, stdout io.Writer) error {
+	lang := appCfg.BackendLang
+	flags := flag.NewFlagSet("csv", flag.ContinueOnError)
+	columns := flags.String("columns", "", "Comma-separated column names or 1-based indexes to convert")
+	from := flags.String("from", string(steamIDFormatAuto), "Source format, or auto to detect it per cell")
+	to := flags.String("to", "", "Target format")
+	mode := flags.String("mode", csvModeAppend, "append adds <column>_<to> columns; replace rewrites the cells in place")
+	noHeader := flags.Bool("no-header", false, "Treat the first row as data; columns must then be indexes")
+	delimiterFlag := flags.String("delimiter", ",", "Field delimiter (single character, or tab)")
+	universe := flags.String("universe", appCfg.SID2Universe, "SteamID2 universe when to=sid2 (0 or 1)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *to == "" {
+		return errors.New(msg("target_param_required", lang))
+	}
+	delimiter, ok := parseCSVDelimiter(*delimiterFlag)
+	if !ok {
+		return errors.New(msgf("csv_delimiter_invalid", lang, *delimiterFlag))
+	}
+	if !isValidSID2Universe(*universe) {
+		return errors.New(localizedErrorMessage(ErrorInvalidUniverse, lang))
+	}
+
+	converter, convErr, message := newCSVConverter(stdin, csvOptions{
+		Columns:   splitCSVColumns(*columns),
+		From:      *from,
+		To:        *to,
+		Mode:      *mode,
+		Header:    !*noHeader,
+		Delimiter: delimiter,
+		Convert:   conversionOptions{SID2Universe: *universe},
+	}, lang)
+	if !convErr.IsValid() {
+		if message == "" {
+			message = localizedErrorMessage(convErr, lang)
+		}
+		return errors.New(message)
+	}
+
+	output := bufio.NewWriter(stdout)
+	if _, err := converter.writeTo(context.Background(), output, nil); err != nil {
+		_ = output.Flush()
+		return err
+	}
+	return output.Flush()
+}
@@ -0,0 +1,107 @@
+package app
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandleCSVAppendsConvertedAndErrorColumns(t *testing.T) {
+	t.Parallel()
+
+	body := "player,steamid,kills\n" +
+		"alice,STEAM_1:0:11101,10\n" +
+		"bob,[U:1:22203],4\n" +
+		"carol,not-an-id,7\n" +
+		"dave\n"
+	req := httptest.NewRequest(http.MethodPost, EndpointCSV+"?columns=SteamID&to=sid64", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	HandleCSV(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %q", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	if contentType := rec.Header().Get("Content-Type"); contentType != "text/csv; charset=utf-8" {
+		t.Fatalf("unexpected content type %q", contentType)
+	}
+
+	want := "player,steamid,kills,steamid_sid64,steamid_error\n" +
+		"alice,STEAM_1:0:11101,10,76561197960287930,\n" +
+		"bob,[U:1:22203],4,76561197960287931,\n" +
+		"carol,not-an-id,7,,invalid_format\n" +
+		"dave,,,,missing_parameter\n"
+	if got := rec.Body.String(); got != want {
+		t.Fatalf("unexpected body:\n%s", got)
+	}
+}
+
+func TestHandleCSVReplaceModeKeepsFailedCells(t *testing.T) {
+	t.Parallel()
+
+	body := "76561197960287930;76561197960287931\n123;76561197960287932\n"
+	req := httptest.NewRequest(http.MethodPost, EndpointCSV+"?columns=1,2&from=sid64&to=sid2&mode=replace&header=0&delimiter=%3B&universe=0", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	HandleCSV(rec, req)
+
+	want := "STEAM_0:0:11101;STEAM_0:1:11101;;\n" +
+		"123;STEAM_0:0:11102;invalid_length;\n"
+	if got := rec.Body.String(); rec.Code != http.StatusOK || got != want {
+		t.Fatalf("unexpected response %d:\n%s", rec.Code, got)
+	}
+}
+
+func TestHandleCSVRejectsInvalidRequests(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		query    string
+		body     string
+		wantBody string
+	}{
+		{name: "missing target", query: "?columns=steamid", body: "steamid\n1\n", wantBody: "to parameter required"},
+		{name: "missing columns", query: "?to=sid64", body: "steamid\n1\n", wantBody: "columns parameter required"},
+		{name: "unknown column", query: "?columns=sid&to=sid64", body: "steamid\n1\n", wantBody: "unknown CSV column \"sid\""},
+		{name: "index out of range", query: "?columns=3&to=sid64", body: "steamid\n1\n", wantBody: "unknown CSV column \"3\""},
+		{name: "bad mode", query: "?columns=steamid&to=sid64&mode=merge", body: "steamid\n1\n", wantBody: "invalid CSV mode \"merge\" (expected append or replace)"},
+		{name: "empty input", query: "?columns=steamid&to=sid64", body: "", wantBody: "CSV input is empty"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodPost, EndpointCSV+tc.query, strings.NewReader(tc.body))
+			rec := httptest.NewRecorder()
+
+			HandleCSV(rec, req)
+
+			if rec.Code != http.StatusBadRequest || rec.Body.String() != tc.wantBody {
+				t.Fatalf("unexpected response %d %q", rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestRunCSVCommand(t *testing.T) {
+	t.Parallel()
+
+	input := strings.NewReader("id\n22202\n")
+	var output bytes.Buffer
+
+	if err := runCSVCommand([]string{"-columns", "id", "-from", "aid", "-to", "sid3"}, input, &output); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := output.String(); got != "id,id_sid3,id_error\n22202,[U:1:22202],\n" {
+		t.Fatalf("unexpected output %q", got)
+	}
+
+	if err := runCSVCommand([]string{"-columns", "id"}, strings.NewReader("id\n1\n"), &output); err == nil || err.Error() != "to parameter required" {
+		t.Fatalf("expected missing target error, got %v", err)
+	}
+}
@@ -0,0 +1,132 @@
+package app
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"steamid-service/pkg/keyvalues"
+	"steamid-service/pkg/steamid"
+)
+
+const maxAccountIDLength = 10
+
+func detectSteamIDFormat(input string) steamIDFormat {
+	lower := strings.ToLower(input)
+	switch {
+	case input == "":
+		return steamIDFormatUnknown
+	case strings.HasPrefix(input, "STEAM_"):
+		return steamIDFormatSID2
+	case strings.HasPrefix(input, "["):
+		return steamIDFormatSID3
+	case strings.HasPrefix(lower, SteamIDHexPrefix), strings.HasPrefix(lower, "0x"):
+		return steamIDFormatHex
+	case strings.HasPrefix(lower, "http://"), strings.HasPrefix(lower, "https://"),
+		strings.HasPrefix(lower, SteamCommunityHost), strings.HasPrefix(lower, "www."+SteamCommunityHost):
+		return steamIDFormatURL
+	case steamid.IsASCIIUnsignedDecimal(input) && len(input) <= maxAccountIDLength:
+		return steamIDFormatAID
+	case steamid.IsASCIIUnsignedDecimal(input):
+		return steamIDFormatSID64
+	}
+
+	return steamIDFormatUnknown
+}
+
+type steamIDDescription struct {
+	Input             string `json:"input"`
+	InputFormat       string `json:"input_format"`
+	AccountID         string `json:"accountid"`
+	SteamID2          string `json:"steamid2,omitempty"`
+	SteamID2Universe0 string `json:"steamid2_universe0,omitempty"`
+	SteamID2Universe1 string `json:"steamid2_universe1,omitempty"`
+	SteamID3          string `json:"steamid3,omitempty"`
+	SteamID64         string `json:"steamid64"`
+	Hex               string `json:"hex"`
+	ProfileURL        string `json:"profile_url,omitempty"`
+	Universe          string `json:"universe"`
+	UniverseID        uint8  `json:"universe_id"`
+	AccountType       string `json:"type"`
+	AccountTypeID     uint8  `json:"type_id"`
+	Instance          uint32 `json:"instance"`
+}
+
+func describeSteamID(ctx context.Context, input, lang string, opts conversionOptions) (steamIDDescription, conversionExecutionResult) {
+	format := detectSteamIDFormat(input)
+	spec, ok := lookupSteamIDFormat(string(format))
+	if format == steamIDFormatUnknown || !ok {
+		return steamIDDescription{}, conversionExecutionResult{
+			Error:        ErrorInvalidFormat,
+			ErrorContext: msgf("input", lang, input),
+		}
+	}
+
+	result := runConversionSteps(ctx, input, lang, opts, []conversionStep{spec.toSteamID64})
+	if !result.Error.IsValid() {
+		return steamIDDescription{}, result
+	}
+
+	id, err := steamid.ParseSteamID64(result.Value)
+	if err != nil {
+		return steamIDDescription{}, conversionExecutionResult{Error: steamIDErrorFrom(err), ErrorContext: result.Value}
+	}
+
+	return newSteamIDDescription(input, format, id, opts), result
+}
+
+func newSteamIDDescription(input string, format steamIDFormat, id steamid.SteamID, opts conversionOptions) steamIDDescription {
+	description := steamIDDescription{
+		Input:         input,
+		InputFormat:   string(format),
+		AccountID:     steamid.FormatAccountID(id),
+		SteamID64:     id.SteamID64(),
+		Hex:           steamid.FormatHex(id),
+		Universe:      id.Universe.String(),
+		UniverseID:    uint8(id.Universe),
+		AccountType:   id.Type.String(),
+		AccountTypeID: uint8(id.Type),
+		Instance:      id.Instance,
+	}
+
+	if sid3, err := steamid.FormatSteamID3(id); err == nil {
+		description.SteamID3 = sid3
+	}
+	if profileURL, err := steamid.FormatProfileURL(id); err == nil {
+		description.ProfileURL = profileURL
+	}
+	if sid2, err := formatSteamID2(id, opts.SID2Universe); err == nil {
+		description.SteamID2 = sid2
+		description.SteamID2Universe0, _ = steamid.FormatSteamID2(id, steamid.UniverseInvalid)
+		description.SteamID2Universe1, _ = steamid.FormatSteamID2(id, steamid.UniversePublic)
+	}
+
+	return description
+}
+
+func formatDescriptionAsKeyValue(description steamIDDescription, sectionName string) string {
+	section := keyvalues.NewSection(sectionName)
+	section.AddValue("input", description.Input)
+	section.AddValue("input_format", description.InputFormat)
+	section.AddValue("accountid", description.AccountID)
+	if description.SteamID2 != "" {
+		section.AddValue("steamid2", description.SteamID2)
+		section.AddValue("steamid2_universe0", description.SteamID2Universe0)
+		section.AddValue("steamid2_universe1", description.SteamID2Universe1)
+	}
+	if description.SteamID3 != "" {
+		section.AddValue("steamid3", description.SteamID3)
+	}
+	section.AddValue("steamid64", description.SteamID64)
+	section.AddValue("hex", description.Hex)
+	if description.ProfileURL != "" {
+		section.AddValue("profile_url", description.ProfileURL)
+	}
+	section.AddValue("universe", description.Universe)
+	section.AddValue("universe_id", strconv.FormatUint(uint64(description.UniverseID), 10))
+	section.AddValue("type", description.AccountType)
+	section.AddValue("type_id", strconv.FormatUint(uint64(description.AccountTypeID), 10))
+	section.AddValue("instance", strconv.FormatUint(uint64(description.Instance), 10))
+
+	return string(keyvalues.Marshal(section))
+}
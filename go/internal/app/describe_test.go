@@ -0,0 +1,139 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDescribeSteamIDAcceptsEveryInputFormat(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		input      string
+		wantFormat steamIDFormat
+	}{
+		{input: "76561197960287930", wantFormat: steamIDFormatSID64},
+		{input: "STEAM_0:0:11101", wantFormat: steamIDFormatSID2},
+		{input: "[U:1:22202]", wantFormat: steamIDFormatSID3},
+		{input: "22202", wantFormat: steamIDFormatAID},
+		{input: "steam:1100001000056ba", wantFormat: steamIDFormatHex},
+		{input: "0x01100001000056BA", wantFormat: steamIDFormatHex},
+		{input: "https://steamcommunity.com/profiles/76561197960287930/", wantFormat: steamIDFormatURL},
+		{input: "steamcommunity.com/profiles/76561197960287930", wantFormat: steamIDFormatURL},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.input, func(t *testing.T) {
+			t.Parallel()
+
+			description, result := describeSteamID(context.Background(), tc.input, "en", conversionOptions{SID2Universe: "1"})
+			if result.Error != ErrorNone {
+				t.Fatalf("expected no error, got %q", result.Error)
+			}
+			if description.InputFormat != string(tc.wantFormat) {
+				t.Fatalf("expected format %q, got %q", tc.wantFormat, description.InputFormat)
+			}
+			if description.SteamID64 != "76561197960287930" {
+				t.Fatalf("unexpected steamid64 %q", description.SteamID64)
+			}
+			if description.SteamID2Universe0 != "STEAM_0:0:11101" || description.SteamID2Universe1 != "STEAM_1:0:11101" {
+				t.Fatalf("unexpected steamid2 values %+v", description)
+			}
+		})
+	}
+}
+
+func TestDescribeSteamIDRejectsUnknownInput(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		input   string
+		wantErr SteamIDError
+	}{
+		{input: "not-a-steamid", wantErr: ErrorInvalidFormat},
+		{input: "https://steamcommunity.com/id/vanity", wantErr: ErrorInvalidFormat},
+		{input: "https://example.com/profiles/76561197960287930", wantErr: ErrorInvalidFormat},
+		{input: "123456789012", wantErr: ErrorInvalidLength},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.input, func(t *testing.T) {
+			t.Parallel()
+
+			if _, result := describeSteamID(context.Background(), tc.input, "en", conversionOptions{SID2Universe: "1"}); result.Error != tc.wantErr {
+				t.Fatalf("expected error %q, got %q", tc.wantErr, result.Error)
+			}
+		})
+	}
+}
+
+func TestHandleDescribeKeyValueOutput(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, EndpointDescribe+"?steamid=[U:1:22202]", nil)
+	rec := httptest.NewRecorder()
+
+	HandleDescribe(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	expected := "\"SteamIDTools\"\n{\n" +
+		"    \"input\" \"[U:1:22202]\"\n" +
+		"    \"input_format\" \"sid3\"\n" +
+		"    \"accountid\" \"22202\"\n" +
+		"    \"steamid2\" \"STEAM_1:0:11101\"\n" +
+		"    \"steamid2_universe0\" \"STEAM_0:0:11101\"\n" +
+		"    \"steamid2_universe1\" \"STEAM_1:0:11101\"\n" +
+		"    \"steamid3\" \"[U:1:22202]\"\n" +
+		"    \"steamid64\" \"76561197960287930\"\n" +
+		"    \"hex\" \"steam:1100001000056ba\"\n" +
+		"    \"profile_url\" \"https://steamcommunity.com/profiles/76561197960287930\"\n" +
+		"    \"universe\" \"public\"\n" +
+		"    \"universe_id\" \"1\"\n" +
+		"    \"type\" \"individual\"\n" +
+		"    \"type_id\" \"1\"\n" +
+		"    \"instance\" \"1\"\n" +
+		"}"
+
+	if body := rec.Body.String(); body != expected {
+		t.Fatalf("unexpected body:\n%s", body)
+	}
+}
+
+func TestHandleDescribeJSONOutputForClan(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, EndpointDescribe+"?steamid=103582791429521412", nil)
+	req.Header.Set("Accept", "application/json")
+	rec := httptest.NewRecorder()
+
+	HandleDescribe(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/json; charset=utf-8" {
+		t.Fatalf("unexpected content type %q", got)
+	}
+
+	var description map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &description); err != nil {
+		t.Fatalf("expected valid JSON, got error: %v", err)
+	}
+
+	if got := description["steamid3"]; got != "[g:1:4]" {
+		t.Fatalf("unexpected steamid3 %v", got)
+	}
+	if got := description["type"]; got != "clan" {
+		t.Fatalf("unexpected type %v", got)
+	}
+	if got := description["profile_url"]; got != "https://steamcommunity.com/gid/103582791429521412" {
+		t.Fatalf("unexpected profile_url %v", got)
+	}
+	if _, ok := description["steamid2"]; ok {
+		t.Fatal("expected no steamid2 for clan IDs")
+	}
+}
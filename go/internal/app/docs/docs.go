@@ -17,9 +17,15 @@ const docTemplate = `{
     "paths": {
         "/AIDtoSID64": {
             "get": {
-                "description": "Converts one AccountID value to SteamID64. Supports comma-separated batch input via the steamid query parameter.",
+                "description": "Converts one AccountID value to SteamID64. Supports comma-separated batch input via the steamid query parameter, or a POST body (newline-delimited text, JSON array or Valve KeyValue section) for larger batches.",
+                "consumes": [
+                    "text/plain",
+                    "application/json"
+                ],
                 "produces": [
-                    "text/plain"
+                    "text/plain",
+                    "application/json",
+                    "application/octet-stream"
                 ],
                 "tags": [
                     "conversion"
@@ -33,11 +39,62 @@ const docTemplate = `{
                         "in": "query",
                         "required": true
                     },
+                    {
+                        "type": "string",
+                        "description": "Set to json for {input, value} or, in batch, an array of items with error code and localized message; kvbinary for binary Valve KeyValues with numeric error codes (for HTTP clients; the SourceMod plugin transports cannot read it)",
+                        "name": "format",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "description": "Append a NUL terminator to the plain-text response",
                         "name": "nullterm",
                         "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "reject",
+                            "dedupe",
+                            "positional"
+                        ],
+                        "type": "string",
+                        "description": "Batch mode: reject (default) fails on duplicates, dedupe collapses them, positional keys every slot by index; naming a mode appends a summary block",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "raw",
+                            "account"
+                        ],
+                        "type": "string",
+                        "description": "Set to account to detect duplicates by account across formats (STEAM_0/STEAM_1/[U:1:N]); batch items then report their canonical account and merged aliases",
+                        "name": "identity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum batch items per page; the response ends with a cursor key while more pages remain",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Client buffer size per page in bytes, counting the NUL terminator, such as 4096 for STEAMIDTOOLS_MAX_RESULT_LENGTH",
+                        "name": "max_bytes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page; send the same batch again with it to get the next page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Set to 1 (or send Prefer: respond-async) to run a batch as a background job; the response holds the job ID to poll at /jobs/{id}",
+                        "name": "async",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -53,6 +110,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "413": {
+                        "description": "POST body exceeds MAX_BATCH_BODY_BYTES",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "503": {
                         "description": "Service unavailable",
                         "schema": {
@@ -60,31 +123,86 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/SID2toSID64": {
-            "get": {
-                "description": "Converts one SteamID2 value to SteamID64. Supports comma-separated batch input via the steamid query parameter.",
+            },
+            "post": {
+                "description": "Converts one AccountID value to SteamID64. Supports comma-separated batch input via the steamid query parameter, or a POST body (newline-delimited text, JSON array or Valve KeyValue section) for larger batches.",
+                "consumes": [
+                    "text/plain",
+                    "application/json"
+                ],
                 "produces": [
-                    "text/plain"
+                    "text/plain",
+                    "application/json",
+                    "application/octet-stream"
                 ],
                 "tags": [
                     "conversion"
                 ],
-                "summary": "Convert SteamID2 to SteamID64",
+                "summary": "Convert AccountID to SteamID64",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "SteamID2 value or comma-separated SteamID2 batch",
+                        "description": "AccountID value or comma-separated AccountID batch",
                         "name": "steamid",
                         "in": "query",
                         "required": true
                     },
+                    {
+                        "type": "string",
+                        "description": "Set to json for {input, value} or, in batch, an array of items with error code and localized message; kvbinary for binary Valve KeyValues with numeric error codes (for HTTP clients; the SourceMod plugin transports cannot read it)",
+                        "name": "format",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "description": "Append a NUL terminator to the plain-text response",
                         "name": "nullterm",
                         "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "reject",
+                            "dedupe",
+                            "positional"
+                        ],
+                        "type": "string",
+                        "description": "Batch mode: reject (default) fails on duplicates, dedupe collapses them, positional keys every slot by index; naming a mode appends a summary block",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "raw",
+                            "account"
+                        ],
+                        "type": "string",
+                        "description": "Set to account to detect duplicates by account across formats (STEAM_0/STEAM_1/[U:1:N]); batch items then report their canonical account and merged aliases",
+                        "name": "identity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum batch items per page; the response ends with a cursor key while more pages remain",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Client buffer size per page in bytes, counting the NUL terminator, such as 4096 for STEAMIDTOOLS_MAX_RESULT_LENGTH",
+                        "name": "max_bytes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page; send the same batch again with it to get the next page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Set to 1 (or send Prefer: respond-async) to run a batch as a background job; the response holds the job ID to poll at /jobs/{id}",
+                        "name": "async",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -100,6 +218,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "413": {
+                        "description": "POST body exceeds MAX_BATCH_BODY_BYTES",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "503": {
                         "description": "Service unavailable",
                         "schema": {
@@ -109,34 +233,87 @@ const docTemplate = `{
                 }
             }
         },
-        "/SID3toSID64": {
+        "/ClanIDtoGID3": {
             "get": {
-                "description": "Converts one SteamID3 value to SteamID64. Supports comma-separated batch input via the steamid query parameter.",
+                "description": "Converts one clan AccountID value to group SteamID3. Supports comma-separated batch input via the steamid query parameter, or a POST body (newline-delimited text, JSON array or Valve KeyValue section) for larger batches.",
                 "produces": [
-                    "text/plain"
+                    "text/plain",
+                    "application/json",
+                    "application/octet-stream"
                 ],
                 "tags": [
-                    "conversion"
+                    "groups"
                 ],
-                "summary": "Convert SteamID3 to SteamID64",
+                "summary": "Convert clan AccountID to group SteamID3",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "SteamID3 value or comma-separated SteamID3 batch",
+                        "description": "Clan AccountID value or comma-separated clan AccountID batch",
                         "name": "steamid",
                         "in": "query",
                         "required": true
                     },
+                    {
+                        "type": "string",
+                        "description": "Set to json for {input, value} or, in batch, an array of items with error code and localized message; kvbinary for binary Valve KeyValues with numeric error codes (for HTTP clients; the SourceMod plugin transports cannot read it)",
+                        "name": "format",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "description": "Append a NUL terminator to the plain-text response",
                         "name": "nullterm",
                         "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "reject",
+                            "dedupe",
+                            "positional"
+                        ],
+                        "type": "string",
+                        "description": "Batch mode: reject (default) fails on duplicates, dedupe collapses them, positional keys every slot by index; naming a mode appends a summary block",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "raw",
+                            "account"
+                        ],
+                        "type": "string",
+                        "description": "Set to account to detect duplicates by account across formats (STEAM_0/STEAM_1/[U:1:N]); batch items then report their canonical account and merged aliases",
+                        "name": "identity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum batch items per page; the response ends with a cursor key while more pages remain",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Client buffer size per page in bytes, counting the NUL terminator, such as 4096 for STEAMIDTOOLS_MAX_RESULT_LENGTH",
+                        "name": "max_bytes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page; send the same batch again with it to get the next page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Set to 1 (or send Prefer: respond-async) to run a batch as a background job; the response holds the job ID to poll at /jobs/{id}",
+                        "name": "async",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Converted SteamID64 or Valve KeyValue batch response",
+                        "description": "Converted group SteamID3 or Valve KeyValue batch response",
                         "schema": {
                             "type": "string"
                         }
@@ -147,6 +324,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "413": {
+                        "description": "POST body exceeds MAX_BATCH_BODY_BYTES",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "503": {
                         "description": "Service unavailable",
                         "schema": {
@@ -154,36 +337,87 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/SID64toAID": {
-            "get": {
-                "description": "Converts one SteamID64 value to AccountID. Supports comma-separated batch input via the steamid query parameter.",
+            },
+            "post": {
+                "description": "Converts one clan AccountID value to group SteamID3. Supports comma-separated batch input via the steamid query parameter, or a POST body (newline-delimited text, JSON array or Valve KeyValue section) for larger batches.",
                 "produces": [
-                    "text/plain"
+                    "text/plain",
+                    "application/json",
+                    "application/octet-stream"
                 ],
                 "tags": [
-                    "conversion"
+                    "groups"
                 ],
-                "summary": "Convert SteamID64 to AccountID",
+                "summary": "Convert clan AccountID to group SteamID3",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "SteamID64 value or comma-separated SteamID64 batch",
+                        "description": "Clan AccountID value or comma-separated clan AccountID batch",
                         "name": "steamid",
                         "in": "query",
                         "required": true
                     },
+                    {
+                        "type": "string",
+                        "description": "Set to json for {input, value} or, in batch, an array of items with error code and localized message; kvbinary for binary Valve KeyValues with numeric error codes (for HTTP clients; the SourceMod plugin transports cannot read it)",
+                        "name": "format",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "description": "Append a NUL terminator to the plain-text response",
                         "name": "nullterm",
                         "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "reject",
+                            "dedupe",
+                            "positional"
+                        ],
+                        "type": "string",
+                        "description": "Batch mode: reject (default) fails on duplicates, dedupe collapses them, positional keys every slot by index; naming a mode appends a summary block",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "raw",
+                            "account"
+                        ],
+                        "type": "string",
+                        "description": "Set to account to detect duplicates by account across formats (STEAM_0/STEAM_1/[U:1:N]); batch items then report their canonical account and merged aliases",
+                        "name": "identity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum batch items per page; the response ends with a cursor key while more pages remain",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Client buffer size per page in bytes, counting the NUL terminator, such as 4096 for STEAMIDTOOLS_MAX_RESULT_LENGTH",
+                        "name": "max_bytes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page; send the same batch again with it to get the next page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Set to 1 (or send Prefer: respond-async) to run a batch as a background job; the response holds the job ID to poll at /jobs/{id}",
+                        "name": "async",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Converted AccountID or Valve KeyValue batch response",
+                        "description": "Converted group SteamID3 or Valve KeyValue batch response",
                         "schema": {
                             "type": "string"
                         }
@@ -194,6 +428,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "413": {
+                        "description": "POST body exceeds MAX_BATCH_BODY_BYTES",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "503": {
                         "description": "Service unavailable",
                         "schema": {
@@ -203,34 +443,87 @@ const docTemplate = `{
                 }
             }
         },
-        "/SID64toSID2": {
+        "/ClanIDtoGID64": {
             "get": {
-                "description": "Converts one SteamID64 value to SteamID2. Supports comma-separated batch input via the steamid query parameter.",
+                "description": "Converts one clan AccountID value to group SteamID64. Supports comma-separated batch input via the steamid query parameter, or a POST body (newline-delimited text, JSON array or Valve KeyValue section) for larger batches.",
                 "produces": [
-                    "text/plain"
+                    "text/plain",
+                    "application/json",
+                    "application/octet-stream"
                 ],
                 "tags": [
-                    "conversion"
+                    "groups"
                 ],
-                "summary": "Convert SteamID64 to SteamID2",
+                "summary": "Convert clan AccountID to group SteamID64",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "SteamID64 value or comma-separated SteamID64 batch",
+                        "description": "Clan AccountID value or comma-separated clan AccountID batch",
                         "name": "steamid",
                         "in": "query",
                         "required": true
                     },
+                    {
+                        "type": "string",
+                        "description": "Set to json for {input, value} or, in batch, an array of items with error code and localized message; kvbinary for binary Valve KeyValues with numeric error codes (for HTTP clients; the SourceMod plugin transports cannot read it)",
+                        "name": "format",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "description": "Append a NUL terminator to the plain-text response",
                         "name": "nullterm",
                         "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "reject",
+                            "dedupe",
+                            "positional"
+                        ],
+                        "type": "string",
+                        "description": "Batch mode: reject (default) fails on duplicates, dedupe collapses them, positional keys every slot by index; naming a mode appends a summary block",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "raw",
+                            "account"
+                        ],
+                        "type": "string",
+                        "description": "Set to account to detect duplicates by account across formats (STEAM_0/STEAM_1/[U:1:N]); batch items then report their canonical account and merged aliases",
+                        "name": "identity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum batch items per page; the response ends with a cursor key while more pages remain",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Client buffer size per page in bytes, counting the NUL terminator, such as 4096 for STEAMIDTOOLS_MAX_RESULT_LENGTH",
+                        "name": "max_bytes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page; send the same batch again with it to get the next page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Set to 1 (or send Prefer: respond-async) to run a batch as a background job; the response holds the job ID to poll at /jobs/{id}",
+                        "name": "async",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Converted SteamID2 or Valve KeyValue batch response",
+                        "description": "Converted group SteamID64 or Valve KeyValue batch response",
                         "schema": {
                             "type": "string"
                         }
@@ -241,6 +534,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "413": {
+                        "description": "POST body exceeds MAX_BATCH_BODY_BYTES",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "503": {
                         "description": "Service unavailable",
                         "schema": {
@@ -248,36 +547,87 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/SID64toSID3": {
-            "get": {
-                "description": "Converts one SteamID64 value to SteamID3. Supports comma-separated batch input via the steamid query parameter.",
+            },
+            "post": {
+                "description": "Converts one clan AccountID value to group SteamID64. Supports comma-separated batch input via the steamid query parameter, or a POST body (newline-delimited text, JSON array or Valve KeyValue section) for larger batches.",
                 "produces": [
-                    "text/plain"
+                    "text/plain",
+                    "application/json",
+                    "application/octet-stream"
                 ],
                 "tags": [
-                    "conversion"
+                    "groups"
                 ],
-                "summary": "Convert SteamID64 to SteamID3",
+                "summary": "Convert clan AccountID to group SteamID64",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "SteamID64 value or comma-separated SteamID64 batch",
+                        "description": "Clan AccountID value or comma-separated clan AccountID batch",
                         "name": "steamid",
                         "in": "query",
                         "required": true
                     },
+                    {
+                        "type": "string",
+                        "description": "Set to json for {input, value} or, in batch, an array of items with error code and localized message; kvbinary for binary Valve KeyValues with numeric error codes (for HTTP clients; the SourceMod plugin transports cannot read it)",
+                        "name": "format",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "description": "Append a NUL terminator to the plain-text response",
                         "name": "nullterm",
                         "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "reject",
+                            "dedupe",
+                            "positional"
+                        ],
+                        "type": "string",
+                        "description": "Batch mode: reject (default) fails on duplicates, dedupe collapses them, positional keys every slot by index; naming a mode appends a summary block",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "raw",
+                            "account"
+                        ],
+                        "type": "string",
+                        "description": "Set to account to detect duplicates by account across formats (STEAM_0/STEAM_1/[U:1:N]); batch items then report their canonical account and merged aliases",
+                        "name": "identity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum batch items per page; the response ends with a cursor key while more pages remain",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Client buffer size per page in bytes, counting the NUL terminator, such as 4096 for STEAMIDTOOLS_MAX_RESULT_LENGTH",
+                        "name": "max_bytes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page; send the same batch again with it to get the next page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Set to 1 (or send Prefer: respond-async) to run a batch as a background job; the response holds the job ID to poll at /jobs/{id}",
+                        "name": "async",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Converted SteamID3 or Valve KeyValue batch response",
+                        "description": "Converted group SteamID64 or Valve KeyValue batch response",
                         "schema": {
                             "type": "string"
                         }
@@ -288,6 +638,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "413": {
+                        "description": "POST body exceeds MAX_BATCH_BODY_BYTES",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "503": {
                         "description": "Service unavailable",
                         "schema": {
@@ -297,29 +653,2728 @@ const docTemplate = `{
                 }
             }
         },
-        "/health": {
+        "/GID3toClanID": {
             "get": {
-                "description": "Returns the backend health status after a self-check conversion.",
+                "description": "Converts one group SteamID3 value to clan AccountID. Supports comma-separated batch input via the steamid query parameter, or a POST body (newline-delimited text, JSON array or Valve KeyValue section) for larger batches.",
                 "produces": [
-                    "text/plain"
+                    "text/plain",
+                    "application/json",
+                    "application/octet-stream"
                 ],
                 "tags": [
-                    "health"
+                    "groups"
                 ],
-                "summary": "Health check",
-                "responses": {
-                    "200": {
-                        "description": "HEALTHY",
-                        "schema": {
-                            "type": "string"
-                        }
+                "summary": "Convert group SteamID3 to clan AccountID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group SteamID3 value or comma-separated group SteamID3 batch",
+                        "name": "steamid",
+                        "in": "query",
+                        "required": true
                     },
-                    "503": {
-                        "description": "UNHEALTHY",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
+                    {
+                        "type": "string",
+                        "description": "Set to json for {input, value} or, in batch, an array of items with error code and localized message; kvbinary for binary Valve KeyValues with numeric error codes (for HTTP clients; the SourceMod plugin transports cannot read it)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Append a NUL terminator to the plain-text response",
+                        "name": "nullterm",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "reject",
+                            "dedupe",
+                            "positional"
+                        ],
+                        "type": "string",
+                        "description": "Batch mode: reject (default) fails on duplicates, dedupe collapses them, positional keys every slot by index; naming a mode appends a summary block",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "raw",
+                            "account"
+                        ],
+                        "type": "string",
+                        "description": "Set to account to detect duplicates by account across formats (STEAM_0/STEAM_1/[U:1:N]); batch items then report their canonical account and merged aliases",
+                        "name": "identity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum batch items per page; the response ends with a cursor key while more pages remain",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Client buffer size per page in bytes, counting the NUL terminator, such as 4096 for STEAMIDTOOLS_MAX_RESULT_LENGTH",
+                        "name": "max_bytes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page; send the same batch again with it to get the next page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Set to 1 (or send Prefer: respond-async) to run a batch as a background job; the response holds the job ID to poll at /jobs/{id}",
+                        "name": "async",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Converted clan AccountID or Valve KeyValue batch response",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "POST body exceeds MAX_BATCH_BODY_BYTES",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Service unavailable",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Converts one group SteamID3 value to clan AccountID. Supports comma-separated batch input via the steamid query parameter, or a POST body (newline-delimited text, JSON array or Valve KeyValue section) for larger batches.",
+                "produces": [
+                    "text/plain",
+                    "application/json",
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Convert group SteamID3 to clan AccountID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group SteamID3 value or comma-separated group SteamID3 batch",
+                        "name": "steamid",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to json for {input, value} or, in batch, an array of items with error code and localized message; kvbinary for binary Valve KeyValues with numeric error codes (for HTTP clients; the SourceMod plugin transports cannot read it)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Append a NUL terminator to the plain-text response",
+                        "name": "nullterm",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "reject",
+                            "dedupe",
+                            "positional"
+                        ],
+                        "type": "string",
+                        "description": "Batch mode: reject (default) fails on duplicates, dedupe collapses them, positional keys every slot by index; naming a mode appends a summary block",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "raw",
+                            "account"
+                        ],
+                        "type": "string",
+                        "description": "Set to account to detect duplicates by account across formats (STEAM_0/STEAM_1/[U:1:N]); batch items then report their canonical account and merged aliases",
+                        "name": "identity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum batch items per page; the response ends with a cursor key while more pages remain",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Client buffer size per page in bytes, counting the NUL terminator, such as 4096 for STEAMIDTOOLS_MAX_RESULT_LENGTH",
+                        "name": "max_bytes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page; send the same batch again with it to get the next page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Set to 1 (or send Prefer: respond-async) to run a batch as a background job; the response holds the job ID to poll at /jobs/{id}",
+                        "name": "async",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Converted clan AccountID or Valve KeyValue batch response",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "POST body exceeds MAX_BATCH_BODY_BYTES",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Service unavailable",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/GID3toGID64": {
+            "get": {
+                "description": "Converts one group SteamID3 value to group SteamID64. Supports comma-separated batch input via the steamid query parameter, or a POST body (newline-delimited text, JSON array or Valve KeyValue section) for larger batches.",
+                "produces": [
+                    "text/plain",
+                    "application/json",
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Convert group SteamID3 to group SteamID64",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group SteamID3 value or comma-separated group SteamID3 batch",
+                        "name": "steamid",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to json for {input, value} or, in batch, an array of items with error code and localized message; kvbinary for binary Valve KeyValues with numeric error codes (for HTTP clients; the SourceMod plugin transports cannot read it)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Append a NUL terminator to the plain-text response",
+                        "name": "nullterm",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "reject",
+                            "dedupe",
+                            "positional"
+                        ],
+                        "type": "string",
+                        "description": "Batch mode: reject (default) fails on duplicates, dedupe collapses them, positional keys every slot by index; naming a mode appends a summary block",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "raw",
+                            "account"
+                        ],
+                        "type": "string",
+                        "description": "Set to account to detect duplicates by account across formats (STEAM_0/STEAM_1/[U:1:N]); batch items then report their canonical account and merged aliases",
+                        "name": "identity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum batch items per page; the response ends with a cursor key while more pages remain",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Client buffer size per page in bytes, counting the NUL terminator, such as 4096 for STEAMIDTOOLS_MAX_RESULT_LENGTH",
+                        "name": "max_bytes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page; send the same batch again with it to get the next page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Set to 1 (or send Prefer: respond-async) to run a batch as a background job; the response holds the job ID to poll at /jobs/{id}",
+                        "name": "async",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Converted group SteamID64 or Valve KeyValue batch response",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "POST body exceeds MAX_BATCH_BODY_BYTES",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Service unavailable",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Converts one group SteamID3 value to group SteamID64. Supports comma-separated batch input via the steamid query parameter, or a POST body (newline-delimited text, JSON array or Valve KeyValue section) for larger batches.",
+                "produces": [
+                    "text/plain",
+                    "application/json",
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Convert group SteamID3 to group SteamID64",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group SteamID3 value or comma-separated group SteamID3 batch",
+                        "name": "steamid",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to json for {input, value} or, in batch, an array of items with error code and localized message; kvbinary for binary Valve KeyValues with numeric error codes (for HTTP clients; the SourceMod plugin transports cannot read it)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Append a NUL terminator to the plain-text response",
+                        "name": "nullterm",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "reject",
+                            "dedupe",
+                            "positional"
+                        ],
+                        "type": "string",
+                        "description": "Batch mode: reject (default) fails on duplicates, dedupe collapses them, positional keys every slot by index; naming a mode appends a summary block",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "raw",
+                            "account"
+                        ],
+                        "type": "string",
+                        "description": "Set to account to detect duplicates by account across formats (STEAM_0/STEAM_1/[U:1:N]); batch items then report their canonical account and merged aliases",
+                        "name": "identity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum batch items per page; the response ends with a cursor key while more pages remain",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Client buffer size per page in bytes, counting the NUL terminator, such as 4096 for STEAMIDTOOLS_MAX_RESULT_LENGTH",
+                        "name": "max_bytes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page; send the same batch again with it to get the next page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Set to 1 (or send Prefer: respond-async) to run a batch as a background job; the response holds the job ID to poll at /jobs/{id}",
+                        "name": "async",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Converted group SteamID64 or Valve KeyValue batch response",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "POST body exceeds MAX_BATCH_BODY_BYTES",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Service unavailable",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/GID64toClanID": {
+            "get": {
+                "description": "Converts one group SteamID64 value to clan AccountID. Supports comma-separated batch input via the steamid query parameter, or a POST body (newline-delimited text, JSON array or Valve KeyValue section) for larger batches.",
+                "produces": [
+                    "text/plain",
+                    "application/json",
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Convert group SteamID64 to clan AccountID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Steam group SteamID64 value or comma-separated group SteamID64 batch",
+                        "name": "steamid",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to json for {input, value} or, in batch, an array of items with error code and localized message; kvbinary for binary Valve KeyValues with numeric error codes (for HTTP clients; the SourceMod plugin transports cannot read it)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Append a NUL terminator to the plain-text response",
+                        "name": "nullterm",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "reject",
+                            "dedupe",
+                            "positional"
+                        ],
+                        "type": "string",
+                        "description": "Batch mode: reject (default) fails on duplicates, dedupe collapses them, positional keys every slot by index; naming a mode appends a summary block",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "raw",
+                            "account"
+                        ],
+                        "type": "string",
+                        "description": "Set to account to detect duplicates by account across formats (STEAM_0/STEAM_1/[U:1:N]); batch items then report their canonical account and merged aliases",
+                        "name": "identity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum batch items per page; the response ends with a cursor key while more pages remain",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Client buffer size per page in bytes, counting the NUL terminator, such as 4096 for STEAMIDTOOLS_MAX_RESULT_LENGTH",
+                        "name": "max_bytes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page; send the same batch again with it to get the next page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Set to 1 (or send Prefer: respond-async) to run a batch as a background job; the response holds the job ID to poll at /jobs/{id}",
+                        "name": "async",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Converted clan AccountID or Valve KeyValue batch response",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "POST body exceeds MAX_BATCH_BODY_BYTES",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Service unavailable",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Converts one group SteamID64 value to clan AccountID. Supports comma-separated batch input via the steamid query parameter, or a POST body (newline-delimited text, JSON array or Valve KeyValue section) for larger batches.",
+                "produces": [
+                    "text/plain",
+                    "application/json",
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Convert group SteamID64 to clan AccountID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Steam group SteamID64 value or comma-separated group SteamID64 batch",
+                        "name": "steamid",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to json for {input, value} or, in batch, an array of items with error code and localized message; kvbinary for binary Valve KeyValues with numeric error codes (for HTTP clients; the SourceMod plugin transports cannot read it)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Append a NUL terminator to the plain-text response",
+                        "name": "nullterm",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "reject",
+                            "dedupe",
+                            "positional"
+                        ],
+                        "type": "string",
+                        "description": "Batch mode: reject (default) fails on duplicates, dedupe collapses them, positional keys every slot by index; naming a mode appends a summary block",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "raw",
+                            "account"
+                        ],
+                        "type": "string",
+                        "description": "Set to account to detect duplicates by account across formats (STEAM_0/STEAM_1/[U:1:N]); batch items then report their canonical account and merged aliases",
+                        "name": "identity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum batch items per page; the response ends with a cursor key while more pages remain",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Client buffer size per page in bytes, counting the NUL terminator, such as 4096 for STEAMIDTOOLS_MAX_RESULT_LENGTH",
+                        "name": "max_bytes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page; send the same batch again with it to get the next page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Set to 1 (or send Prefer: respond-async) to run a batch as a background job; the response holds the job ID to poll at /jobs/{id}",
+                        "name": "async",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Converted clan AccountID or Valve KeyValue batch response",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "POST body exceeds MAX_BATCH_BODY_BYTES",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Service unavailable",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/GID64toGID3": {
+            "get": {
+                "description": "Converts one group SteamID64 value to group SteamID3. Supports comma-separated batch input via the steamid query parameter, or a POST body (newline-delimited text, JSON array or Valve KeyValue section) for larger batches.",
+                "produces": [
+                    "text/plain",
+                    "application/json",
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Convert group SteamID64 to group SteamID3",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Steam group SteamID64 value or comma-separated group SteamID64 batch",
+                        "name": "steamid",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to json for {input, value} or, in batch, an array of items with error code and localized message; kvbinary for binary Valve KeyValues with numeric error codes (for HTTP clients; the SourceMod plugin transports cannot read it)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Append a NUL terminator to the plain-text response",
+                        "name": "nullterm",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "reject",
+                            "dedupe",
+                            "positional"
+                        ],
+                        "type": "string",
+                        "description": "Batch mode: reject (default) fails on duplicates, dedupe collapses them, positional keys every slot by index; naming a mode appends a summary block",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "raw",
+                            "account"
+                        ],
+                        "type": "string",
+                        "description": "Set to account to detect duplicates by account across formats (STEAM_0/STEAM_1/[U:1:N]); batch items then report their canonical account and merged aliases",
+                        "name": "identity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum batch items per page; the response ends with a cursor key while more pages remain",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Client buffer size per page in bytes, counting the NUL terminator, such as 4096 for STEAMIDTOOLS_MAX_RESULT_LENGTH",
+                        "name": "max_bytes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page; send the same batch again with it to get the next page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Set to 1 (or send Prefer: respond-async) to run a batch as a background job; the response holds the job ID to poll at /jobs/{id}",
+                        "name": "async",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Converted group SteamID3 or Valve KeyValue batch response",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "POST body exceeds MAX_BATCH_BODY_BYTES",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Service unavailable",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Converts one group SteamID64 value to group SteamID3. Supports comma-separated batch input via the steamid query parameter, or a POST body (newline-delimited text, JSON array or Valve KeyValue section) for larger batches.",
+                "produces": [
+                    "text/plain",
+                    "application/json",
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Convert group SteamID64 to group SteamID3",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Steam group SteamID64 value or comma-separated group SteamID64 batch",
+                        "name": "steamid",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to json for {input, value} or, in batch, an array of items with error code and localized message; kvbinary for binary Valve KeyValues with numeric error codes (for HTTP clients; the SourceMod plugin transports cannot read it)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Append a NUL terminator to the plain-text response",
+                        "name": "nullterm",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "reject",
+                            "dedupe",
+                            "positional"
+                        ],
+                        "type": "string",
+                        "description": "Batch mode: reject (default) fails on duplicates, dedupe collapses them, positional keys every slot by index; naming a mode appends a summary block",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "raw",
+                            "account"
+                        ],
+                        "type": "string",
+                        "description": "Set to account to detect duplicates by account across formats (STEAM_0/STEAM_1/[U:1:N]); batch items then report their canonical account and merged aliases",
+                        "name": "identity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum batch items per page; the response ends with a cursor key while more pages remain",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Client buffer size per page in bytes, counting the NUL terminator, such as 4096 for STEAMIDTOOLS_MAX_RESULT_LENGTH",
+                        "name": "max_bytes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page; send the same batch again with it to get the next page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Set to 1 (or send Prefer: respond-async) to run a batch as a background job; the response holds the job ID to poll at /jobs/{id}",
+                        "name": "async",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Converted group SteamID3 or Valve KeyValue batch response",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "POST body exceeds MAX_BATCH_BODY_BYTES",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Service unavailable",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/SID2toSID64": {
+            "get": {
+                "description": "Converts one SteamID2 value to SteamID64. Supports comma-separated batch input via the steamid query parameter, or a POST body (newline-delimited text, JSON array or Valve KeyValue section) for larger batches.",
+                "consumes": [
+                    "text/plain",
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain",
+                    "application/json",
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "conversion"
+                ],
+                "summary": "Convert SteamID2 to SteamID64",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "SteamID2 value or comma-separated SteamID2 batch",
+                        "name": "steamid",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to json for {input, value} or, in batch, an array of items with error code and localized message; kvbinary for binary Valve KeyValues with numeric error codes (for HTTP clients; the SourceMod plugin transports cannot read it)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Append a NUL terminator to the plain-text response",
+                        "name": "nullterm",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "reject",
+                            "dedupe",
+                            "positional"
+                        ],
+                        "type": "string",
+                        "description": "Batch mode: reject (default) fails on duplicates, dedupe collapses them, positional keys every slot by index; naming a mode appends a summary block",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "raw",
+                            "account"
+                        ],
+                        "type": "string",
+                        "description": "Set to account to detect duplicates by account across formats (STEAM_0/STEAM_1/[U:1:N]); batch items then report their canonical account and merged aliases",
+                        "name": "identity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum batch items per page; the response ends with a cursor key while more pages remain",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Client buffer size per page in bytes, counting the NUL terminator, such as 4096 for STEAMIDTOOLS_MAX_RESULT_LENGTH",
+                        "name": "max_bytes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page; send the same batch again with it to get the next page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Set to 1 (or send Prefer: respond-async) to run a batch as a background job; the response holds the job ID to poll at /jobs/{id}",
+                        "name": "async",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Converted SteamID64 or Valve KeyValue batch response",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "POST body exceeds MAX_BATCH_BODY_BYTES",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Service unavailable",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Converts one SteamID2 value to SteamID64. Supports comma-separated batch input via the steamid query parameter, or a POST body (newline-delimited text, JSON array or Valve KeyValue section) for larger batches.",
+                "consumes": [
+                    "text/plain",
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain",
+                    "application/json",
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "conversion"
+                ],
+                "summary": "Convert SteamID2 to SteamID64",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "SteamID2 value or comma-separated SteamID2 batch",
+                        "name": "steamid",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to json for {input, value} or, in batch, an array of items with error code and localized message; kvbinary for binary Valve KeyValues with numeric error codes (for HTTP clients; the SourceMod plugin transports cannot read it)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Append a NUL terminator to the plain-text response",
+                        "name": "nullterm",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "reject",
+                            "dedupe",
+                            "positional"
+                        ],
+                        "type": "string",
+                        "description": "Batch mode: reject (default) fails on duplicates, dedupe collapses them, positional keys every slot by index; naming a mode appends a summary block",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "raw",
+                            "account"
+                        ],
+                        "type": "string",
+                        "description": "Set to account to detect duplicates by account across formats (STEAM_0/STEAM_1/[U:1:N]); batch items then report their canonical account and merged aliases",
+                        "name": "identity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum batch items per page; the response ends with a cursor key while more pages remain",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Client buffer size per page in bytes, counting the NUL terminator, such as 4096 for STEAMIDTOOLS_MAX_RESULT_LENGTH",
+                        "name": "max_bytes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page; send the same batch again with it to get the next page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Set to 1 (or send Prefer: respond-async) to run a batch as a background job; the response holds the job ID to poll at /jobs/{id}",
+                        "name": "async",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Converted SteamID64 or Valve KeyValue batch response",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "POST body exceeds MAX_BATCH_BODY_BYTES",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Service unavailable",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/SID3toSID64": {
+            "get": {
+                "description": "Converts one SteamID3 value to SteamID64. Supports comma-separated batch input via the steamid query parameter, or a POST body (newline-delimited text, JSON array or Valve KeyValue section) for larger batches.",
+                "consumes": [
+                    "text/plain",
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain",
+                    "application/json",
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "conversion"
+                ],
+                "summary": "Convert SteamID3 to SteamID64",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "SteamID3 value or comma-separated SteamID3 batch",
+                        "name": "steamid",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to json for {input, value} or, in batch, an array of items with error code and localized message; kvbinary for binary Valve KeyValues with numeric error codes (for HTTP clients; the SourceMod plugin transports cannot read it)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Append a NUL terminator to the plain-text response",
+                        "name": "nullterm",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "reject",
+                            "dedupe",
+                            "positional"
+                        ],
+                        "type": "string",
+                        "description": "Batch mode: reject (default) fails on duplicates, dedupe collapses them, positional keys every slot by index; naming a mode appends a summary block",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "raw",
+                            "account"
+                        ],
+                        "type": "string",
+                        "description": "Set to account to detect duplicates by account across formats (STEAM_0/STEAM_1/[U:1:N]); batch items then report their canonical account and merged aliases",
+                        "name": "identity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum batch items per page; the response ends with a cursor key while more pages remain",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Client buffer size per page in bytes, counting the NUL terminator, such as 4096 for STEAMIDTOOLS_MAX_RESULT_LENGTH",
+                        "name": "max_bytes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page; send the same batch again with it to get the next page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Set to 1 (or send Prefer: respond-async) to run a batch as a background job; the response holds the job ID to poll at /jobs/{id}",
+                        "name": "async",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Converted SteamID64 or Valve KeyValue batch response",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "POST body exceeds MAX_BATCH_BODY_BYTES",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Service unavailable",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Converts one SteamID3 value to SteamID64. Supports comma-separated batch input via the steamid query parameter, or a POST body (newline-delimited text, JSON array or Valve KeyValue section) for larger batches.",
+                "consumes": [
+                    "text/plain",
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain",
+                    "application/json",
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "conversion"
+                ],
+                "summary": "Convert SteamID3 to SteamID64",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "SteamID3 value or comma-separated SteamID3 batch",
+                        "name": "steamid",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to json for {input, value} or, in batch, an array of items with error code and localized message; kvbinary for binary Valve KeyValues with numeric error codes (for HTTP clients; the SourceMod plugin transports cannot read it)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Append a NUL terminator to the plain-text response",
+                        "name": "nullterm",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "reject",
+                            "dedupe",
+                            "positional"
+                        ],
+                        "type": "string",
+                        "description": "Batch mode: reject (default) fails on duplicates, dedupe collapses them, positional keys every slot by index; naming a mode appends a summary block",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "raw",
+                            "account"
+                        ],
+                        "type": "string",
+                        "description": "Set to account to detect duplicates by account across formats (STEAM_0/STEAM_1/[U:1:N]); batch items then report their canonical account and merged aliases",
+                        "name": "identity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum batch items per page; the response ends with a cursor key while more pages remain",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Client buffer size per page in bytes, counting the NUL terminator, such as 4096 for STEAMIDTOOLS_MAX_RESULT_LENGTH",
+                        "name": "max_bytes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page; send the same batch again with it to get the next page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Set to 1 (or send Prefer: respond-async) to run a batch as a background job; the response holds the job ID to poll at /jobs/{id}",
+                        "name": "async",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Converted SteamID64 or Valve KeyValue batch response",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "POST body exceeds MAX_BATCH_BODY_BYTES",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Service unavailable",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/SID64toAID": {
+            "get": {
+                "description": "Converts one SteamID64 value to AccountID. Supports comma-separated batch input via the steamid query parameter, or a POST body (newline-delimited text, JSON array or Valve KeyValue section) for larger batches.",
+                "consumes": [
+                    "text/plain",
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain",
+                    "application/json",
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "conversion"
+                ],
+                "summary": "Convert SteamID64 to AccountID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "SteamID64 value or comma-separated SteamID64 batch",
+                        "name": "steamid",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to json for {input, value} or, in batch, an array of items with error code and localized message; kvbinary for binary Valve KeyValues with numeric error codes (for HTTP clients; the SourceMod plugin transports cannot read it)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Append a NUL terminator to the plain-text response",
+                        "name": "nullterm",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "reject",
+                            "dedupe",
+                            "positional"
+                        ],
+                        "type": "string",
+                        "description": "Batch mode: reject (default) fails on duplicates, dedupe collapses them, positional keys every slot by index; naming a mode appends a summary block",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "raw",
+                            "account"
+                        ],
+                        "type": "string",
+                        "description": "Set to account to detect duplicates by account across formats (STEAM_0/STEAM_1/[U:1:N]); batch items then report their canonical account and merged aliases",
+                        "name": "identity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum batch items per page; the response ends with a cursor key while more pages remain",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Client buffer size per page in bytes, counting the NUL terminator, such as 4096 for STEAMIDTOOLS_MAX_RESULT_LENGTH",
+                        "name": "max_bytes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page; send the same batch again with it to get the next page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Set to 1 (or send Prefer: respond-async) to run a batch as a background job; the response holds the job ID to poll at /jobs/{id}",
+                        "name": "async",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Converted AccountID or Valve KeyValue batch response",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "POST body exceeds MAX_BATCH_BODY_BYTES",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Service unavailable",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Converts one SteamID64 value to AccountID. Supports comma-separated batch input via the steamid query parameter, or a POST body (newline-delimited text, JSON array or Valve KeyValue section) for larger batches.",
+                "consumes": [
+                    "text/plain",
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain",
+                    "application/json",
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "conversion"
+                ],
+                "summary": "Convert SteamID64 to AccountID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "SteamID64 value or comma-separated SteamID64 batch",
+                        "name": "steamid",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to json for {input, value} or, in batch, an array of items with error code and localized message; kvbinary for binary Valve KeyValues with numeric error codes (for HTTP clients; the SourceMod plugin transports cannot read it)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Append a NUL terminator to the plain-text response",
+                        "name": "nullterm",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "reject",
+                            "dedupe",
+                            "positional"
+                        ],
+                        "type": "string",
+                        "description": "Batch mode: reject (default) fails on duplicates, dedupe collapses them, positional keys every slot by index; naming a mode appends a summary block",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "raw",
+                            "account"
+                        ],
+                        "type": "string",
+                        "description": "Set to account to detect duplicates by account across formats (STEAM_0/STEAM_1/[U:1:N]); batch items then report their canonical account and merged aliases",
+                        "name": "identity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum batch items per page; the response ends with a cursor key while more pages remain",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Client buffer size per page in bytes, counting the NUL terminator, such as 4096 for STEAMIDTOOLS_MAX_RESULT_LENGTH",
+                        "name": "max_bytes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page; send the same batch again with it to get the next page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Set to 1 (or send Prefer: respond-async) to run a batch as a background job; the response holds the job ID to poll at /jobs/{id}",
+                        "name": "async",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Converted AccountID or Valve KeyValue batch response",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "POST body exceeds MAX_BATCH_BODY_BYTES",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Service unavailable",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/SID64toSID2": {
+            "get": {
+                "description": "Converts one SteamID64 value to SteamID2. Supports comma-separated batch input via the steamid query parameter, or a POST body (newline-delimited text, JSON array or Valve KeyValue section) for larger batches.",
+                "consumes": [
+                    "text/plain",
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain",
+                    "application/json",
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "conversion"
+                ],
+                "summary": "Convert SteamID64 to SteamID2",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "SteamID64 value or comma-separated SteamID64 batch",
+                        "name": "steamid",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to json for {input, value} or, in batch, an array of items with error code and localized message; kvbinary for binary Valve KeyValues with numeric error codes (for HTTP clients; the SourceMod plugin transports cannot read it)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Append a NUL terminator to the plain-text response",
+                        "name": "nullterm",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "reject",
+                            "dedupe",
+                            "positional"
+                        ],
+                        "type": "string",
+                        "description": "Batch mode: reject (default) fails on duplicates, dedupe collapses them, positional keys every slot by index; naming a mode appends a summary block",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "raw",
+                            "account"
+                        ],
+                        "type": "string",
+                        "description": "Set to account to detect duplicates by account across formats (STEAM_0/STEAM_1/[U:1:N]); batch items then report their canonical account and merged aliases",
+                        "name": "identity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum batch items per page; the response ends with a cursor key while more pages remain",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Client buffer size per page in bytes, counting the NUL terminator, such as 4096 for STEAMIDTOOLS_MAX_RESULT_LENGTH",
+                        "name": "max_bytes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page; send the same batch again with it to get the next page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Set to 1 (or send Prefer: respond-async) to run a batch as a background job; the response holds the job ID to poll at /jobs/{id}",
+                        "name": "async",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "SteamID2 universe override for this request (0 or 1)",
+                        "name": "universe",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "SteamID2 universe override when the universe query parameter is absent (0 or 1)",
+                        "name": "X-SteamIDTools-SID2-Universe",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Converted SteamID2 or Valve KeyValue batch response",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "POST body exceeds MAX_BATCH_BODY_BYTES",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Service unavailable",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Converts one SteamID64 value to SteamID2. Supports comma-separated batch input via the steamid query parameter, or a POST body (newline-delimited text, JSON array or Valve KeyValue section) for larger batches.",
+                "consumes": [
+                    "text/plain",
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain",
+                    "application/json",
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "conversion"
+                ],
+                "summary": "Convert SteamID64 to SteamID2",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "SteamID64 value or comma-separated SteamID64 batch",
+                        "name": "steamid",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to json for {input, value} or, in batch, an array of items with error code and localized message; kvbinary for binary Valve KeyValues with numeric error codes (for HTTP clients; the SourceMod plugin transports cannot read it)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Append a NUL terminator to the plain-text response",
+                        "name": "nullterm",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "reject",
+                            "dedupe",
+                            "positional"
+                        ],
+                        "type": "string",
+                        "description": "Batch mode: reject (default) fails on duplicates, dedupe collapses them, positional keys every slot by index; naming a mode appends a summary block",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "raw",
+                            "account"
+                        ],
+                        "type": "string",
+                        "description": "Set to account to detect duplicates by account across formats (STEAM_0/STEAM_1/[U:1:N]); batch items then report their canonical account and merged aliases",
+                        "name": "identity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum batch items per page; the response ends with a cursor key while more pages remain",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Client buffer size per page in bytes, counting the NUL terminator, such as 4096 for STEAMIDTOOLS_MAX_RESULT_LENGTH",
+                        "name": "max_bytes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page; send the same batch again with it to get the next page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Set to 1 (or send Prefer: respond-async) to run a batch as a background job; the response holds the job ID to poll at /jobs/{id}",
+                        "name": "async",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "SteamID2 universe override for this request (0 or 1)",
+                        "name": "universe",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "SteamID2 universe override when the universe query parameter is absent (0 or 1)",
+                        "name": "X-SteamIDTools-SID2-Universe",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Converted SteamID2 or Valve KeyValue batch response",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "POST body exceeds MAX_BATCH_BODY_BYTES",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Service unavailable",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/SID64toSID3": {
+            "get": {
+                "description": "Converts one SteamID64 value to SteamID3. Supports comma-separated batch input via the steamid query parameter, or a POST body (newline-delimited text, JSON array or Valve KeyValue section) for larger batches.",
+                "consumes": [
+                    "text/plain",
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain",
+                    "application/json",
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "conversion"
+                ],
+                "summary": "Convert SteamID64 to SteamID3",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "SteamID64 value or comma-separated SteamID64 batch",
+                        "name": "steamid",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to json for {input, value} or, in batch, an array of items with error code and localized message; kvbinary for binary Valve KeyValues with numeric error codes (for HTTP clients; the SourceMod plugin transports cannot read it)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Append a NUL terminator to the plain-text response",
+                        "name": "nullterm",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "reject",
+                            "dedupe",
+                            "positional"
+                        ],
+                        "type": "string",
+                        "description": "Batch mode: reject (default) fails on duplicates, dedupe collapses them, positional keys every slot by index; naming a mode appends a summary block",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "raw",
+                            "account"
+                        ],
+                        "type": "string",
+                        "description": "Set to account to detect duplicates by account across formats (STEAM_0/STEAM_1/[U:1:N]); batch items then report their canonical account and merged aliases",
+                        "name": "identity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum batch items per page; the response ends with a cursor key while more pages remain",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Client buffer size per page in bytes, counting the NUL terminator, such as 4096 for STEAMIDTOOLS_MAX_RESULT_LENGTH",
+                        "name": "max_bytes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page; send the same batch again with it to get the next page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Set to 1 (or send Prefer: respond-async) to run a batch as a background job; the response holds the job ID to poll at /jobs/{id}",
+                        "name": "async",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Converted SteamID3 or Valve KeyValue batch response",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "POST body exceeds MAX_BATCH_BODY_BYTES",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Service unavailable",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Converts one SteamID64 value to SteamID3. Supports comma-separated batch input via the steamid query parameter, or a POST body (newline-delimited text, JSON array or Valve KeyValue section) for larger batches.",
+                "consumes": [
+                    "text/plain",
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain",
+                    "application/json",
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "conversion"
+                ],
+                "summary": "Convert SteamID64 to SteamID3",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "SteamID64 value or comma-separated SteamID64 batch",
+                        "name": "steamid",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to json for {input, value} or, in batch, an array of items with error code and localized message; kvbinary for binary Valve KeyValues with numeric error codes (for HTTP clients; the SourceMod plugin transports cannot read it)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Append a NUL terminator to the plain-text response",
+                        "name": "nullterm",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "reject",
+                            "dedupe",
+                            "positional"
+                        ],
+                        "type": "string",
+                        "description": "Batch mode: reject (default) fails on duplicates, dedupe collapses them, positional keys every slot by index; naming a mode appends a summary block",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "raw",
+                            "account"
+                        ],
+                        "type": "string",
+                        "description": "Set to account to detect duplicates by account across formats (STEAM_0/STEAM_1/[U:1:N]); batch items then report their canonical account and merged aliases",
+                        "name": "identity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum batch items per page; the response ends with a cursor key while more pages remain",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Client buffer size per page in bytes, counting the NUL terminator, such as 4096 for STEAMIDTOOLS_MAX_RESULT_LENGTH",
+                        "name": "max_bytes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page; send the same batch again with it to get the next page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Set to 1 (or send Prefer: respond-async) to run a batch as a background job; the response holds the job ID to poll at /jobs/{id}",
+                        "name": "async",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Converted SteamID3 or Valve KeyValue batch response",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "POST body exceeds MAX_BATCH_BODY_BYTES",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Service unavailable",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/convert": {
+            "get": {
+                "description": "Converts between any registered pair of formats. Account formats (sid64, aid, sid2, sid3, hex, url) convert among themselves, and group formats (gid64, clanid, gid3) convert among themselves. Every pair is also served as a fixed route such as /SID2toSID3. With from=auto each input's format is detected on its own, so one batch may mix formats; the batch response then reports the detected format per item. Supports comma-separated batch input via the steamid query parameter, or a POST body (newline-delimited text, JSON array or Valve KeyValue section) for larger batches.",
+                "consumes": [
+                    "text/plain",
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain",
+                    "application/json",
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "conversion"
+                ],
+                "summary": "Convert between any two formats",
+                "parameters": [
+                    {
+                        "enum": [
+                            "auto",
+                            "sid64",
+                            "aid",
+                            "sid2",
+                            "sid3",
+                            "hex",
+                            "url",
+                            "gid64",
+                            "clanid",
+                            "gid3"
+                        ],
+                        "type": "string",
+                        "description": "Source format, or auto to detect it per input",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "sid64",
+                            "aid",
+                            "sid2",
+                            "sid3",
+                            "hex",
+                            "url",
+                            "gid64",
+                            "clanid",
+                            "gid3"
+                        ],
+                        "type": "string",
+                        "description": "Target format; required unless targets is set",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated target formats, such as sid2,sid3; returns one section (or JSON object) per input with one key per target and takes precedence over to",
+                        "name": "targets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Value or comma-separated batch in the source format",
+                        "name": "steamid",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to json for {input, value} or, in batch, an array of items with error code and localized message; kvbinary for binary Valve KeyValues with numeric error codes (for HTTP clients; the SourceMod plugin transports cannot read it)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Append a NUL terminator to the plain-text response",
+                        "name": "nullterm",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "reject",
+                            "dedupe",
+                            "positional"
+                        ],
+                        "type": "string",
+                        "description": "Batch mode: reject (default) fails on duplicates, dedupe collapses them, positional keys every slot by index; naming a mode appends a summary block",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "raw",
+                            "account"
+                        ],
+                        "type": "string",
+                        "description": "Set to account to detect duplicates by account across formats (STEAM_0/STEAM_1/[U:1:N]); batch items then report their canonical account and merged aliases",
+                        "name": "identity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum batch items per page; the response ends with a cursor key while more pages remain",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Client buffer size per page in bytes, counting the NUL terminator, such as 4096 for STEAMIDTOOLS_MAX_RESULT_LENGTH",
+                        "name": "max_bytes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page; send the same batch again with it to get the next page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Set to 1 (or send Prefer: respond-async) to run a batch as a background job; the response holds the job ID to poll at /jobs/{id}",
+                        "name": "async",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "SteamID2 universe override when to=sid2 (0 or 1)",
+                        "name": "universe",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Converted value or Valve KeyValue batch response",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error or unsupported conversion pair",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "POST body exceeds MAX_BATCH_BODY_BYTES",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Converts between any registered pair of formats. Account formats (sid64, aid, sid2, sid3, hex, url) convert among themselves, and group formats (gid64, clanid, gid3) convert among themselves. Every pair is also served as a fixed route such as /SID2toSID3. With from=auto each input's format is detected on its own, so one batch may mix formats; the batch response then reports the detected format per item. Supports comma-separated batch input via the steamid query parameter, or a POST body (newline-delimited text, JSON array or Valve KeyValue section) for larger batches.",
+                "consumes": [
+                    "text/plain",
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain",
+                    "application/json",
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "conversion"
+                ],
+                "summary": "Convert between any two formats",
+                "parameters": [
+                    {
+                        "enum": [
+                            "auto",
+                            "sid64",
+                            "aid",
+                            "sid2",
+                            "sid3",
+                            "hex",
+                            "url",
+                            "gid64",
+                            "clanid",
+                            "gid3"
+                        ],
+                        "type": "string",
+                        "description": "Source format, or auto to detect it per input",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "sid64",
+                            "aid",
+                            "sid2",
+                            "sid3",
+                            "hex",
+                            "url",
+                            "gid64",
+                            "clanid",
+                            "gid3"
+                        ],
+                        "type": "string",
+                        "description": "Target format; required unless targets is set",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated target formats, such as sid2,sid3; returns one section (or JSON object) per input with one key per target and takes precedence over to",
+                        "name": "targets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Value or comma-separated batch in the source format",
+                        "name": "steamid",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to json for {input, value} or, in batch, an array of items with error code and localized message; kvbinary for binary Valve KeyValues with numeric error codes (for HTTP clients; the SourceMod plugin transports cannot read it)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Append a NUL terminator to the plain-text response",
+                        "name": "nullterm",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "reject",
+                            "dedupe",
+                            "positional"
+                        ],
+                        "type": "string",
+                        "description": "Batch mode: reject (default) fails on duplicates, dedupe collapses them, positional keys every slot by index; naming a mode appends a summary block",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "raw",
+                            "account"
+                        ],
+                        "type": "string",
+                        "description": "Set to account to detect duplicates by account across formats (STEAM_0/STEAM_1/[U:1:N]); batch items then report their canonical account and merged aliases",
+                        "name": "identity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum batch items per page; the response ends with a cursor key while more pages remain",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Client buffer size per page in bytes, counting the NUL terminator, such as 4096 for STEAMIDTOOLS_MAX_RESULT_LENGTH",
+                        "name": "max_bytes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page; send the same batch again with it to get the next page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Set to 1 (or send Prefer: respond-async) to run a batch as a background job; the response holds the job ID to poll at /jobs/{id}",
+                        "name": "async",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "SteamID2 universe override when to=sid2 (0 or 1)",
+                        "name": "universe",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Converted value or Valve KeyValue batch response",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error or unsupported conversion pair",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "POST body exceeds MAX_BATCH_BODY_BYTES",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/csv": {
+            "post": {
+                "description": "Reads a CSV POST body and returns it with the selected columns converted. mode=append (default) adds a \u003ccolumn\u003e_\u003cto\u003e column per selected column; mode=replace rewrites the cells in place. Every selected column also gets a \u003ccolumn\u003e_error column holding the error code of a failed cell, so bad rows are annotated instead of aborting the file. The output is streamed.",
+                "consumes": [
+                    "text/csv"
+                ],
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "conversion"
+                ],
+                "summary": "Convert SteamID columns in a CSV file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma-separated header names or 1-based column indexes",
+                        "name": "columns",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "sid64",
+                            "aid",
+                            "sid2",
+                            "sid3",
+                            "hex",
+                            "url",
+                            "gid64",
+                            "clanid",
+                            "gid3"
+                        ],
+                        "type": "string",
+                        "description": "Target format",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "auto",
+                            "sid64",
+                            "aid",
+                            "sid2",
+                            "sid3",
+                            "hex",
+                            "url",
+                            "gid64",
+                            "clanid",
+                            "gid3"
+                        ],
+                        "type": "string",
+                        "description": "Source format, or auto to detect it per cell",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "append",
+                            "replace"
+                        ],
+                        "type": "string",
+                        "description": "append (default) or replace",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Set to 0 when the first row is data; columns must then be indexes",
+                        "name": "header",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Field delimiter, one character or tab (default ,)",
+                        "name": "delimiter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "SteamID2 universe override when to=sid2 (0 or 1)",
+                        "name": "universe",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rewritten CSV",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error, unknown column or unsupported conversion pair",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/describe": {
+            "get": {
+                "description": "Auto-detects SteamID64, SteamID2, SteamID3, AccountID, hex (steam:/0x) or steamcommunity.com profile URL input and returns every representation plus universe, type and instance.",
+                "produces": [
+                    "text/plain",
+                    "application/json"
+                ],
+                "tags": [
+                    "conversion"
+                ],
+                "summary": "Describe any SteamID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "SteamID in any supported format",
+                        "name": "steamid",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to json for a JSON document instead of Valve KeyValue",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "SteamID2 universe used for the steamid2 key (0 or 1)",
+                        "name": "universe",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Append a NUL terminator to the KeyValue response",
+                        "name": "nullterm",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Valve KeyValue or JSON description",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/errors": {
+            "get": {
+                "description": "Lists every error key with its numeric kvbinary code, the HTTP status of a request failing with it and its message in each supported language. Error responses carry the key in the X-SteamIDTools-Error header, and JSON errors are RFC 9457 problem documents whose type is /errors/{key}.",
+                "produces": [
+                    "text/plain",
+                    "application/json"
+                ],
+                "tags": [
+                    "errors"
+                ],
+                "summary": "List error codes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Set to json for a JSON catalog instead of Valve KeyValue",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Valve KeyValue or JSON error catalog",
+                        "schema": {
+                            "$ref": "#/definitions/app.ErrorCatalogResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/errors/{key}": {
+            "get": {
+                "description": "Returns the catalog entry of one error key; this is the type URI of JSON problem responses.",
+                "produces": [
+                    "text/plain",
+                    "application/json"
+                ],
+                "tags": [
+                    "errors"
+                ],
+                "summary": "Describe one error code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Error key, for example invalid_steamid2",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to json for a JSON entry instead of Valve KeyValue",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Valve KeyValue or JSON catalog entry",
+                        "schema": {
+                            "$ref": "#/definitions/app.ErrorCatalogEntry"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown error key",
+                        "schema": {
+                            "$ref": "#/definitions/app.ProblemResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Returns the backend health status after a self-check conversion. Answers 503 once the service is shutting down.",
+                "produces": [
+                    "text/plain",
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Health check",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Set to json for {status} instead of plain text",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "HEALTHY",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "UNHEALTHY",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/jobs/{id}": {
+            "get": {
+                "description": "Returns the status of a batch submitted with async=1 while it is queued or running, then the batch response itself (status code, content type and body as the synchronous request would have returned them). The X-SteamIDTools-Job-Status header always carries the status. Jobs expire JOB_TTL after their last update. /jobs?steamid={id} is accepted as well so SteamIDTools_RequestConversion can poll with API_Jobs.",
+                "produces": [
+                    "text/plain",
+                    "application/json",
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Poll an asynchronous batch job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID returned on submission",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to json or kvbinary for the status document of an unfinished job",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Append a NUL terminator to the KeyValue status document",
+                        "name": "nullterm",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Valve KeyValue job status or the finished batch response",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Job not found or expired",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/metrics": {
+            "get": {
+                "description": "Request counts and latency per endpoint, batch sizes, errors by key, health check outcomes and build info in the Prometheus text format. When METRICS_ADDR is set it is only served on that listener.",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Prometheus metrics",
+                "responses": {
+                    "200": {
+                        "description": "Prometheus text exposition format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/stream": {
+            "post": {
+                "description": "Reads a POST body with one SteamID per line and writes one JSON object per line (input, format, value or error code and localized message) as each input is converted. The response is flushed periodically and the conversion stops when the client disconnects. Input size is not capped; lines longer than 4096 bytes end the stream with an error line. from defaults to auto.",
+                "consumes": [
+                    "text/plain"
+                ],
+                "produces": [
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "conversion"
+                ],
+                "summary": "Stream a large batch as NDJSON",
+                "parameters": [
+                    {
+                        "enum": [
+                            "auto",
+                            "sid64",
+                            "aid",
+                            "sid2",
+                            "sid3",
+                            "hex",
+                            "url",
+                            "gid64",
+                            "clanid",
+                            "gid3"
+                        ],
+                        "type": "string",
+                        "description": "Source format, or auto to detect it per input",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "sid64",
+                            "aid",
+                            "sid2",
+                            "sid3",
+                            "hex",
+                            "url",
+                            "gid64",
+                            "clanid",
+                            "gid3"
+                        ],
+                        "type": "string",
+                        "description": "Target format",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "SteamID2 universe override when to=sid2 (0 or 1)",
+                        "name": "universe",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "NDJSON stream of batch items",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error, unsupported conversion pair or missing POST body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "app.ErrorCatalogEntry": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "messages": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.ErrorCatalogResponse": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/app.ErrorCatalogEntry"
+                    }
+                }
+            }
+        },
+        "app.ProblemResponse": {
+            "type": "object",
+            "properties": {
+                "detail": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "input": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
                 }
             }
         }
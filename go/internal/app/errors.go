@@ -0,0 +1,107 @@
+package app
+
+import (
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"steamid-service/pkg/keyvalues"
+)
+
+// catalogLanguages are the languages whose messages the error catalog lists.
+var catalogLanguages = []string{"en", "es"}
+
+// ErrorCatalogEntry describes one error key: its numeric kvbinary code, the HTTP
+// status of a request that fails with it and its message in each language.
+type ErrorCatalogEntry struct {
+	Key      string            `json:"key"`
+	Code     int32             `json:"code"`
+	Status   int               `json:"status"`
+	Type     string            `json:"type"`
+	Messages map[string]string `json:"messages"`
+}
+
+// ErrorCatalogResponse is the JSON body of /errors.
+type ErrorCatalogResponse struct {
+	Errors []ErrorCatalogEntry `json:"errors"`
+}
+
+// errorTypeURI is the problem type of err; it resolves to its catalog entry.
+func errorTypeURI(err SteamIDError) string {
+	return EndpointErrors + "/" + err.Key()
+}
+
+// errorCatalog lists every error except none, ordered by code.
+func errorCatalog() []ErrorCatalogEntry {
+	keys := make([]SteamIDError, 0, len(errorCodes))
+	for err := range errorCodes {
+		if err != ErrorNone {
+			keys = append(keys, err)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Code() < keys[j].Code() })
+
+	entries := make([]ErrorCatalogEntry, 0, len(keys))
+	for _, err := range keys {
+		entries = append(entries, newErrorCatalogEntry(err))
+	}
+	return entries
+}
+
+func newErrorCatalogEntry(err SteamIDError) ErrorCatalogEntry {
+	status, _ := errorStatus(err)
+	entry := ErrorCatalogEntry{
+		Key:      err.Key(),
+		Code:     err.Code(),
+		Status:   status,
+		Type:     errorTypeURI(err),
+		Messages: make(map[string]string, len(catalogLanguages)),
+	}
+	for _, lang := range catalogLanguages {
+		entry.Messages[lang] = errorTitle(err, lang)
+	}
+	return entry
+}
+
+func errorCatalogSection(entry ErrorCatalogEntry) keyvalues.Node {
+	section := keyvalues.NewSection(entry.Key,
+		keyvalues.NewValue("code", strconv.Itoa(int(entry.Code))),
+		keyvalues.NewValue("status", strconv.Itoa(entry.Status)),
+	)
+	messages := section.AddSection("messages")
+	for _, lang := range catalogLanguages {
+		messages.AddValue(lang, entry.Messages[lang])
+	}
+	return section
+}
+
+// handleErrors serves the whole catalog at /errors and one entry at /errors/{key}.
+func handleErrors(w http.ResponseWriter, r *http.Request) {
+	entries := errorCatalog()
+	if r.URL.Path != EndpointErrors {
+		key := strings.TrimPrefix(r.URL.Path, EndpointErrors+"/")
+		index := slices.IndexFunc(entries, func(entry ErrorCatalogEntry) bool { return entry.Key == key })
+		if index < 0 {
+			writeErrorResponse(w, r, ErrorInvalidEndpoint, msgf("error_key_unknown", getLang(r), key), r.URL.Path)
+			return
+		}
+		if wantsJSON(r) {
+			writeJSONResponse(w, r, http.StatusOK, entries[index])
+			return
+		}
+		entries = entries[index : index+1]
+	}
+
+	if wantsJSON(r) {
+		writeJSONResponse(w, r, http.StatusOK, ErrorCatalogResponse{Errors: entries})
+		return
+	}
+
+	document := keyvalues.NewSection("SteamIDTools")
+	for _, entry := range entries {
+		document.Children = append(document.Children, errorCatalogSection(entry))
+	}
+	writeKeyValueResponse(w, string(keyvalues.Marshal(document)), hasNullTerm(r))
+}
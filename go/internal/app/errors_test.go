@@ -0,0 +1,178 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"steamid-service/pkg/keyvalues"
+)
+
+func TestErrorResponsesCarryMachineReadableKey(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		target     string
+		lang       string
+		wantStatus int
+		wantKey    string
+		wantBody   string
+	}{
+		{
+			name:       "plain text single",
+			target:     EndpointSID2toSID64 + "?steamid=STEAM_1:2:3",
+			lang:       "es",
+			wantStatus: http.StatusBadRequest,
+			wantKey:    "invalid_steamid2",
+			wantBody:   "Formato de SteamID2 inválido (se espera STEAM_X:Y:Z)",
+		},
+		{
+			name:       "problem json single",
+			target:     EndpointSID2toSID64 + "?steamid=STEAM_1:2:3&format=json",
+			wantStatus: http.StatusBadRequest,
+			wantKey:    "invalid_steamid2",
+			wantBody: `{"type":"/errors/invalid_steamid2","title":"Invalid SteamID2 format (expected STEAM_X:Y:Z)",` +
+				`"status":400,"detail":"Invalid SteamID2 format (expected STEAM_X:Y:Z)","error":"invalid_steamid2",` +
+				`"message":"Invalid SteamID2 format (expected STEAM_X:Y:Z)","input":"STEAM_1:2:3"}`,
+		},
+		{
+			name:       "problem json batch duplicate",
+			target:     EndpointSID64toAID + "?steamid=76561197960287930,76561197960287931,76561197960287930&format=json",
+			wantStatus: http.StatusBadRequest,
+			wantKey:    "duplicate_in_batch",
+			wantBody: `{"type":"/errors/duplicate_in_batch","title":"Duplicate SteamID found in batch","status":400,` +
+				`"detail":"Duplicate SteamID found in batch","error":"duplicate_in_batch","message":"Duplicate SteamID found in batch",` +
+				`"input":"76561197960287930","index":2}`,
+		},
+		{
+			name:       "problem json account duplicate",
+			target:     EndpointConvert + "?from=auto&to=sid64&identity=account&steamid=STEAM_0:0:11101,,[U:1:22202]&format=json",
+			wantStatus: http.StatusBadRequest,
+			wantKey:    "duplicate_in_batch",
+			wantBody: `{"type":"/errors/duplicate_in_batch","title":"Duplicate SteamID found in batch","status":400,` +
+				`"detail":"STEAM_0:0:11101 and [U:1:22202] refer to the same account (76561197960287930)","error":"duplicate_in_batch",` +
+				`"message":"STEAM_0:0:11101 and [U:1:22202] refer to the same account (76561197960287930)","input":"[U:1:22202]","index":2}`,
+		},
+		{
+			name:       "request error has no input",
+			target:     EndpointSID64toAID + "?format=json",
+			wantStatus: http.StatusBadRequest,
+			wantKey:    "missing_parameter",
+			wantBody: `{"type":"/errors/missing_parameter","title":"Missing required parameter","status":400,` +
+				`"detail":"steamid parameter required","error":"missing_parameter","message":"steamid parameter required"}`,
+		},
+		{
+			name:       "invalid endpoint",
+			target:     "/nope",
+			wantStatus: http.StatusNotFound,
+			wantKey:    "invalid_endpoint",
+		},
+	}
+
+	mux := newHandlerMux(false)
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			if tc.lang != "" {
+				req.Header.Set("Accept-Language", tc.lang)
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Fatalf("expected status %d, got %d", tc.wantStatus, rec.Code)
+			}
+			if got := rec.Header().Get(ErrorHeader); got != tc.wantKey {
+				t.Fatalf("expected %s %q, got %q", ErrorHeader, tc.wantKey, got)
+			}
+			if tc.wantBody != "" && rec.Body.String() != tc.wantBody {
+				t.Fatalf("unexpected body %q", rec.Body.String())
+			}
+			if strings.Contains(tc.target, "format=json") {
+				if ct := rec.Header().Get("Content-Type"); ct != "application/problem+json; charset=utf-8" {
+					t.Fatalf("unexpected content type %q", ct)
+				}
+			}
+		})
+	}
+}
+
+func TestKeyValueBinaryErrorNamesBatchIndex(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodGet, EndpointSID64toAID+"?format=kvbinary&steamid=76561197960287930,76561197960287930", nil)
+	rec := httptest.NewRecorder()
+	HandleSteamID64ToAccountID(rec, req)
+
+	root, err := keyvalues.UnmarshalBinary(rec.Body.Bytes())
+	if err != nil {
+		t.Fatalf("invalid binary KeyValues: %v", err)
+	}
+	index, _ := root.Get("index")
+	if rec.Code != http.StatusBadRequest || root.String("error_key") != "duplicate_in_batch" ||
+		root.String("input") != "76561197960287930" || index.Kind != keyvalues.KindInt || index.Value != "1" {
+		t.Fatalf("unexpected document %d %+v", rec.Code, root)
+	}
+}
+
+func TestHandleErrorsCatalog(t *testing.T) {
+	t.Parallel()
+
+	mux := newHandlerMux(false)
+
+	t.Run("json lists every code", func(t *testing.T) {
+		t.Parallel()
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, EndpointErrors+"?format=json", nil))
+
+		var catalog ErrorCatalogResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &catalog); err != nil {
+			t.Fatalf("invalid catalog: %v", err)
+		}
+		if len(catalog.Errors) != len(errorCodes)-1 {
+			t.Fatalf("expected %d entries, got %d", len(errorCodes)-1, len(catalog.Errors))
+		}
+		for i, entry := range catalog.Errors {
+			if entry.Code != int32(i+1) {
+				t.Fatalf("entry %d has code %d", i, entry.Code)
+			}
+			for _, lang := range catalogLanguages {
+				if message := entry.Messages[lang]; message == "" || message == entry.Key {
+					t.Fatalf("entry %s has no %s message", entry.Key, lang)
+				}
+			}
+		}
+	})
+
+	t.Run("keyvalue entry", func(t *testing.T) {
+		t.Parallel()
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, EndpointErrors+"/body_too_large", nil))
+
+		want := "\"SteamIDTools\"\n{\n    \"body_too_large\"\n    {\n        \"code\" \"16\"\n        \"status\" \"413\"\n" +
+			"        \"messages\"\n        {\n            \"en\" \"Request body exceeds the configured size limit\"\n" +
+			"            \"es\" \"El cuerpo de la solicitud excede el límite configurado\"\n        }\n    }\n}"
+		if rec.Code != http.StatusOK || rec.Body.String() != want {
+			t.Fatalf("unexpected response %d %q", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		t.Parallel()
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, EndpointErrors+"/nope", nil))
+
+		if rec.Code != http.StatusNotFound || rec.Body.String() != `unknown error key "nope"` {
+			t.Fatalf("unexpected response %d %q", rec.Code, rec.Body.String())
+		}
+	})
+}
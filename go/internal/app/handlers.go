@@ -1,9 +1,15 @@
 package app
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"slices"
 	"strings"
+
+	"steamid-service/pkg/keyvalues"
+	"steamid-service/pkg/steamid"
 )
 
 func getLang(r *http.Request) string {
@@ -28,104 +34,223 @@ func hasNullTerm(r *http.Request) bool {
 	return r.URL.Query().Get("nullterm") == "1"
 }
 
+// wantsKeyValueBinary reports whether format=kvbinary asked for binary KeyValues.
+// It takes precedence over JSON so an Accept header cannot override it.
+func wantsKeyValueBinary(r *http.Request) bool {
+	return r.URL.Query().Get("format") == "kvbinary"
+}
+
+func wantsJSON(r *http.Request) bool {
+	if r.URL.Query().Get("format") == "json" {
+		return true
+	}
+
+	return strings.Contains(r.Header.Get("Accept"), "application/json")
+}
+
+type conversionOptions struct {
+	SID2Universe string
+}
+
 type conversionStep struct {
-	convert      func(string) ConversionResult
-	errorContext func(lang, value string) string
+	convert            func(string) ConversionResult
+	convertWithOptions func(string, conversionOptions) ConversionResult
+	errorContext       func(lang, value string) string
+	from, to           steamIDFormat
 }
 
+func (s conversionStep) run(input string, opts conversionOptions) ConversionResult {
+	if s.convertWithOptions != nil {
+		return s.convertWithOptions(input, opts)
+	}
+
+	return s.convert(input)
+}
+
+// conversionExecutionResult is the outcome of a conversion. Every conversion
+// starts with a toSteamID64 step, so SteamID64 holds the normalized input even
+// when a later step fails; it is empty when the input itself was invalid.
 type conversionExecutionResult struct {
 	Value        string
 	Error        SteamIDError
 	ErrorContext string
+	SteamID64    string
 }
 
 type conversionHandlerConfig struct {
-	RequestLabel string
-	BatchLabel   string
-	Steps        []conversionStep
+	RequestLabel     string
+	BatchLabel       string
+	SourceFormat     steamIDFormat
+	Steps            []conversionStep
+	UsesSID2Universe bool
 }
 
-var (
-	accountIDErrorContext = func(lang, value string) string {
-		return msgf("accountid", lang, value)
+// detectsSource reports whether each input picks its own source format.
+func (cfg conversionHandlerConfig) detectsSource() bool {
+	return cfg.SourceFormat == steamIDFormatAuto
+}
+
+// convertInput runs the configured steps for one input. In auto mode the source
+// format is detected per input and its normalization step is prepended.
+func (cfg conversionHandlerConfig) convertInput(ctx context.Context, input, lang string, opts conversionOptions) (steamIDFormat, conversionExecutionResult) {
+	if !cfg.detectsSource() {
+		return cfg.SourceFormat, runConversionSteps(ctx, input, lang, opts, cfg.Steps)
 	}
 
-	sid64ToAIDConfig = conversionHandlerConfig{
-		RequestLabel: "SID64toAID",
-		BatchLabel:   "SID64->AID",
-		Steps: []conversionStep{
-			{convert: AIDFromSID64},
-		},
+	format := detectSteamIDFormat(input)
+	spec, ok := lookupSteamIDFormat(string(format))
+	if format == steamIDFormatUnknown || !ok {
+		return steamIDFormatUnknown, conversionExecutionResult{
+			Error:        ErrorInvalidFormat,
+			ErrorContext: msgf("input", lang, input),
+		}
 	}
 
-	sid64ToSID2Config = conversionHandlerConfig{
-		RequestLabel: "SID64toSID2",
-		BatchLabel:   "SID64->SID2",
-		Steps: []conversionStep{
-			{convert: AIDFromSID64},
-			{convert: SID2FromAID, errorContext: accountIDErrorContext},
-		},
+	steps := append([]conversionStep{spec.toSteamID64}, cfg.Steps...)
+	return format, runConversionSteps(ctx, input, lang, opts, steps)
+}
+
+// accountOf returns the canonical account SteamID64 of a converted input's
+// SteamID64, so identity grouping reuses the conversion instead of repeating it.
+func accountOf(steamID64 string) (string, bool) {
+	id, err := steamid.ParseSteamID64(steamID64)
+	if err != nil {
+		return "", false
 	}
 
-	sid64ToSID3Config = conversionHandlerConfig{
-		RequestLabel: "SID64toSID3",
-		BatchLabel:   "SID64->SID3",
-		Steps: []conversionStep{
-			{convert: AIDFromSID64},
-			{convert: SID3FromAID, errorContext: accountIDErrorContext},
-		},
+	return id.Account().SteamID64(), true
+}
+
+func defaultConversionOptions() conversionOptions {
+	return conversionOptions{
+		SID2Universe: appCfg.SID2Universe,
 	}
+}
 
-	aidToSID64Config = conversionHandlerConfig{
-		RequestLabel: "AIDtoSID64",
-		BatchLabel:   "AID->SID64",
-		Steps: []conversionStep{
-			{convert: SID64FromAID},
-		},
+func resolveSID2Universe(r *http.Request) (string, SteamIDError) {
+	universe := r.URL.Query().Get(SID2UniverseQueryParam)
+	if universe == "" {
+		universe = strings.TrimSpace(r.Header.Get(SID2UniverseHeader))
 	}
+	if universe == "" {
+		return appCfg.SID2Universe, ErrorNone
+	}
+	if !isValidSID2Universe(universe) {
+		return "", ErrorInvalidUniverse
+	}
+
+	return universe, ErrorNone
+}
 
-	sid2ToSID64Config = conversionHandlerConfig{
-		RequestLabel: "SID2toSID64",
-		BatchLabel:   "SID2->SID64",
-		Steps: []conversionStep{
-			{convert: AIDFromSID2},
-			{convert: SID64FromAID, errorContext: accountIDErrorContext},
-		},
+func resolveConversionOptions(r *http.Request, cfg conversionHandlerConfig) (conversionOptions, SteamIDError) {
+	opts := defaultConversionOptions()
+	if !cfg.UsesSID2Universe {
+		return opts, ErrorNone
 	}
 
-	sid3ToSID64Config = conversionHandlerConfig{
-		RequestLabel: "SID3toSID64",
-		BatchLabel:   "SID3->SID64",
-		Steps: []conversionStep{
-			{convert: AIDFromSID3},
-			{convert: SID64FromAID, errorContext: accountIDErrorContext},
-		},
+	universe, err := resolveSID2Universe(r)
+	if !err.IsValid() {
+		return opts, err
 	}
+	opts.SID2Universe = universe
+
+	return opts, ErrorNone
+}
+
+var (
+	sid64ToAIDConfig    = conversionConfigFor(steamIDFormatSID64, steamIDFormatAID)
+	sid64ToSID2Config   = conversionConfigFor(steamIDFormatSID64, steamIDFormatSID2)
+	sid64ToSID3Config   = conversionConfigFor(steamIDFormatSID64, steamIDFormatSID3)
+	aidToSID64Config    = conversionConfigFor(steamIDFormatAID, steamIDFormatSID64)
+	sid2ToSID64Config   = conversionConfigFor(steamIDFormatSID2, steamIDFormatSID64)
+	sid3ToSID64Config   = conversionConfigFor(steamIDFormatSID3, steamIDFormatSID64)
+	gid64ToClanIDConfig = conversionConfigFor(steamIDFormatGID64, steamIDFormatClanID)
+	gid64ToGID3Config   = conversionConfigFor(steamIDFormatGID64, steamIDFormatGID3)
+	clanIDToGID64Config = conversionConfigFor(steamIDFormatClanID, steamIDFormatGID64)
+	clanIDToGID3Config  = conversionConfigFor(steamIDFormatClanID, steamIDFormatGID3)
+	gid3ToGID64Config   = conversionConfigFor(steamIDFormatGID3, steamIDFormatGID64)
+	gid3ToClanIDConfig  = conversionConfigFor(steamIDFormatGID3, steamIDFormatClanID)
 )
 
-func runConversionSteps(input, lang string, steps []conversionStep) conversionExecutionResult {
+var describeConfig = conversionHandlerConfig{
+	RequestLabel:     "describe",
+	UsesSID2Universe: true,
+}
+
+// serviceEndpoints are the endpoints served next to the conversion routes.
+var serviceEndpoints = []string{EndpointConvert, EndpointStream, EndpointCSV, EndpointJobs, EndpointErrors, EndpointDescribe, EndpointHealth}
+
+func availableEndpoints() []string {
+	routes := conversionRoutes()
+	endpoints := make([]string, 0, len(routes)+len(serviceEndpoints)+1)
+	for _, route := range routes {
+		endpoints = append(endpoints, route.Path)
+	}
+
+	endpoints = append(endpoints, serviceEndpoints...)
+	if !metricsSeparate() {
+		endpoints = append(endpoints, EndpointMetrics)
+	}
+	return endpoints
+}
+
+// runConversionSteps runs steps over input. A single conversion gets its own
+// span with one child per step; inside a batch span the items are not traced one
+// by one, since a batch of thousands would flood the export queue, and failures
+// become capped events on the batch span instead, without the input.
+func runConversionSteps(ctx context.Context, input, lang string, opts conversionOptions, steps []conversionStep) conversionExecutionResult {
+	batch := spanFromContext(ctx)
+	var conversion *span
+	if !batch.aggregatesItems() {
+		batch = nil
+		ctx, conversion = startSpan(ctx, "runConversionSteps")
+		defer conversion.finish()
+		conversion.setAttributes(intAttribute("steamidtools.steps", len(steps)))
+	}
+
 	current := input
+	steamID64 := ""
 
-	for _, step := range steps {
-		result := step.convert(current)
+	for i, step := range steps {
+		var stepSpan *span
+		if batch == nil {
+			_, stepSpan = startSpan(ctx, "conversionStep")
+			stepSpan.setAttributes(
+				stringAttribute("steamidtools.step.from", string(step.from)),
+				stringAttribute("steamidtools.step.to", string(step.to)),
+			)
+		}
+		result := step.run(current, opts)
+		stepSpan.setError(result.Error)
+		stepSpan.finish()
 		if !result.Error.IsValid() {
-			context := current
+			errorContext := current
 			if step.errorContext != nil {
-				context = step.errorContext(lang, current)
+				errorContext = step.errorContext(lang, current)
 			}
+			conversion.setError(result.Error)
+			batch.addEvent("steamidtools.conversion_failed",
+				stringAttribute("steamidtools.error", result.Error.Key()),
+				intAttribute("steamidtools.step", i),
+			)
 
 			return conversionExecutionResult{
 				Error:        result.Error,
-				ErrorContext: context,
+				ErrorContext: errorContext,
+				SteamID64:    steamID64,
 			}
 		}
 
 		current = result.Value
+		if i == 0 {
+			steamID64 = current
+		}
 	}
 
 	return conversionExecutionResult{
-		Value: current,
-		Error: ErrorNone,
+		Value:     current,
+		Error:     ErrorNone,
+		SteamID64: steamID64,
 	}
 }
 
@@ -135,39 +260,154 @@ func newBatchResult(size int) BatchResult {
 	}
 }
 
-func writeBatchParseError(w http.ResponseWriter, r *http.Request, lang, rawInput string, parseErr SteamIDError) {
+func writeBatchParseError(w http.ResponseWriter, r *http.Request, lang, rawInput string, parseErr SteamIDError, limit int, item errorItem) {
 	if parseErr == ErrorInvalidFormat {
-		writeErrorResponse(w, r, parseErr, msgf("batch_limit", lang, appCfg.MaxBatchItems), rawInput)
+		writeErrorResponse(w, r, parseErr, msgf("batch_limit", lang, limit), rawInput)
 		return
 	}
 
-	writeErrorResponse(w, r, parseErr, "", rawInput)
+	writeItemErrorResponse(w, r, parseErr, "", rawInput, item)
 }
 
-func handleBatchConversion(w http.ResponseWriter, r *http.Request, lang, rawInput string, cfg conversionHandlerConfig) {
-	steamids, parseErr := parseBatchInput(rawInput)
+func handleBatchConversion(w http.ResponseWriter, r *http.Request, lang, rawInput string, opts conversionOptions, cfg conversionHandlerConfig) {
+	batch, modeErr, message := resolveBatchOptions(r, lang)
+	if !modeErr.IsValid() {
+		writeErrorResponse(w, r, modeErr, message, "batch mode rejected")
+		return
+	}
+
+	maxItems, _, _ := batchLimits()
+	steamids, parseErr, item := parseBatchInput(rawInput, maxItems, &batch)
+	if !parseErr.IsValid() {
+		writeBatchParseError(w, r, lang, rawInput, parseErr, maxItems, item)
+		return
+	}
+
+	writeBatchConversion(w, r, lang, steamids, opts, cfg, batch)
+}
+
+func handleBatchBodyConversion(w http.ResponseWriter, r *http.Request, lang string, opts conversionOptions, cfg conversionHandlerConfig) {
+	batch, modeErr, message := resolveBatchOptions(r, lang)
+	if !modeErr.IsValid() {
+		writeErrorResponse(w, r, modeErr, message, "batch mode rejected")
+		return
+	}
+
+	steamids, bodyErr, message := readBatchBody(w, r, lang)
+	if !bodyErr.IsValid() {
+		writeErrorResponse(w, r, bodyErr, message, "batch body rejected")
+		return
+	}
+
+	_, maxPostItems, _ := batchLimits()
+	steamids, parseErr, item := validateBatchItems(steamids, maxPostItems, &batch)
 	if !parseErr.IsValid() {
-		writeBatchParseError(w, r, lang, rawInput, parseErr)
+		writeBatchParseError(w, r, lang, "batch body", parseErr, maxPostItems, item)
 		return
 	}
 
+	writeBatchConversion(w, r, lang, steamids, opts, cfg, batch)
+}
+
+func writeBatchConversion(w http.ResponseWriter, r *http.Request, lang string, steamids []string, opts conversionOptions, cfg conversionHandlerConfig, batch batchOptions) {
+	paging, pageErr, message := resolveBatchPaging(r, lang)
+	if !pageErr.IsValid() {
+		writeErrorResponse(w, r, pageErr, message, "batch paging rejected")
+		return
+	}
+
+	ctx, span := startItemsSpan(r.Context(), "batch")
+	defer span.finish()
+	span.setAttributes(
+		stringAttribute("steamidtools.conversion", cfg.BatchLabel),
+		stringAttribute("steamidtools.batch.mode", string(batch.Mode)),
+		intAttribute("steamidtools.batch.items", len(steamids)),
+	)
+
 	batchResult := newBatchResult(len(steamids))
+	batchResult.Positional = batch.Mode == batchModePositional
 	for _, id := range steamids {
 		if id == "" {
+			if batchResult.Positional {
+				batchResult.Items = append(batchResult.Items, BatchItemResult{Input: id, Error: ErrorMissingParameter})
+			}
 			continue
 		}
 
-		result := runConversionSteps(id, lang, cfg.Steps)
-		batchResult.Items = append(batchResult.Items, BatchItemResult{
-			Input: id,
-			Value: result.Value,
-			Error: result.Error,
-		})
+		format, result := cfg.convertInput(ctx, id, lang, opts)
+		item := BatchItemResult{
+			Input:  id,
+			Format: format,
+			Value:  result.Value,
+			Error:  result.Error,
+		}
+		if batch.Identity {
+			item.Account, _ = accountOf(result.SteamID64)
+		}
+		batchResult.Items = append(batchResult.Items, item)
+	}
+	metrics.observeBatchSize(metricsEndpointLabel(r.URL.Path), len(steamids))
+	failed := 0
+	for _, item := range batchResult.Items {
+		if !item.Error.IsValid() {
+			metrics.countError(item.Error, errorScopeItem)
+			failed++
+		}
+	}
+	span.setAttributes(intAttribute("steamidtools.batch.failed", failed))
+	if batch.Identity {
+		if groupErr, message, input := groupByAccount(&batchResult, &batch, lang); !groupErr.IsValid() {
+			// Reject mode has no repeated inputs, so the input locates the entry.
+			item := errorItem{Input: input, Index: slices.Index(steamids, input)}
+			writeItemErrorResponse(w, r, groupErr, message, "batch account duplicate", item)
+			return
+		}
+	}
+	if batch.Summary {
+		summary := newBatchSummary(batchResult, batch.Duplicates)
+		batchResult.Summary = &summary
 	}
 
-	keyValueOutput := formatAsKeyValue(batchResult, "SteamIDTools", lang)
-	writeKeyValueResponse(w, keyValueOutput, hasNullTerm(r))
-	appInfof("batch conversion processed: conversion=%s items=%d remote_addr=%s", cfg.BatchLabel, len(steamids), r.RemoteAddr)
+	detected := cfg.detectsSource()
+	encode := func(results BatchResult) ([]byte, string, error) {
+		return encodeBatchResult(r, results, detected, lang)
+	}
+	page, pageErr, message := paging.page(batchResult, batchDigest(steamids, cfg, batch), encode, lang)
+	if !pageErr.IsValid() {
+		writeErrorResponse(w, r, pageErr, message, "batch cursor rejected")
+		return
+	}
+
+	body, contentType, err := encode(page)
+	writeEncodedResponse(w, r, http.StatusOK, contentType, body, err)
+	appInfof("batch conversion processed: conversion=%s mode=%s items=%d page_items=%d remote_addr=%s", cfg.BatchLabel, batch.Mode, len(steamids), len(page.Items), r.RemoteAddr)
+}
+
+// encodeBatchResult renders a batch in the representation the request asked for.
+func encodeBatchResult(r *http.Request, results BatchResult, detected bool, lang string) ([]byte, string, error) {
+	switch {
+	case wantsKeyValueBinary(r):
+		body, err := keyvalues.MarshalBinary(formatAsBinaryKeyValue(results, "SteamIDTools", detected))
+		return body, contentTypeKeyValueBinary, err
+	case wantsJSON(r):
+		var payload any = localizeBatchItems(results, lang)
+		if results.Summary != nil || results.Paged {
+			payload = BatchResponse{Items: localizeBatchItems(results, lang), Summary: results.Summary, Cursor: results.Cursor}
+		}
+		body, err := json.Marshal(payload)
+		return body, contentTypeJSON, err
+	}
+
+	var content string
+	if detected || results.Identity {
+		content = formatAsSectionedKeyValue(results, "SteamIDTools", detected, lang)
+	} else {
+		content = formatAsKeyValue(results, "SteamIDTools", lang)
+	}
+	if hasNullTerm(r) {
+		content += "\x00"
+	}
+	return []byte(content), contentTypePlainText, nil
 }
 
 func handleConversion(w http.ResponseWriter, r *http.Request, cfg conversionHandlerConfig) {
@@ -175,25 +415,134 @@ func handleConversion(w http.ResponseWriter, r *http.Request, cfg conversionHand
 	logDebug(r, "%s request: %v", cfg.RequestLabel, r.URL.RawQuery)
 
 	steamid := r.URL.Query().Get("steamid")
-	if steamid == "" {
+	if steamid == "" && r.Method != http.MethodPost {
 		writeErrorResponse(w, r, ErrorMissingParameter, msg("steamid_param_required", lang), "steamid query parameter missing")
 		return
 	}
 
-	if strings.Contains(steamid, ",") {
-		handleBatchConversion(w, r, lang, steamid, cfg)
+	opts, optsErr := resolveConversionOptions(r, cfg)
+	if !optsErr.IsValid() {
+		writeErrorResponse(w, r, optsErr, "", "sid2 universe override rejected")
 		return
 	}
 
-	result := runConversionSteps(steamid, lang, cfg.Steps)
+	isBatch := r.Method == http.MethodPost || strings.Contains(steamid, ",")
+	ctx, span := startSpan(r.Context(), "handleConversion")
+	defer span.finish()
+	span.setAttributes(stringAttribute("steamidtools.conversion", cfg.RequestLabel), boolAttribute("steamidtools.batch", isBatch))
+	r = r.WithContext(ctx)
+	if isBatch && wantsAsync(r) {
+		submitBatchJob(w, r, lang, func(w http.ResponseWriter, r *http.Request) {
+			handleConversion(w, r, cfg)
+		})
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		handleBatchBodyConversion(w, r, lang, opts, cfg)
+		return
+	}
+
+	if isBatch {
+		handleBatchConversion(w, r, lang, steamid, opts, cfg)
+		return
+	}
+
+	format, result := cfg.convertInput(r.Context(), steamid, lang, opts)
 	if !result.Error.IsValid() {
-		writeErrorResponse(w, r, result.Error, "", result.ErrorContext)
+		writeItemErrorResponse(w, r, result.Error, "", result.ErrorContext, errorItem{Input: steamid, Index: -1})
+		return
+	}
+
+	if wantsKeyValueBinary(r) {
+		section := keyvalues.NewSection("SteamIDTools", keyvalues.NewValue("input", steamid))
+		if cfg.detectsSource() {
+			section.AddValue("format", string(format))
+		}
+		section.AddValue("value", result.Value)
+		section.Children = append(section.Children, keyvalues.NewInt("error", ErrorNone.Code()))
+		writeKeyValueBinaryResponse(w, r, http.StatusOK, section)
+		return
+	}
+
+	if wantsJSON(r) {
+		response := ConversionResponse{Input: steamid, Value: result.Value}
+		if cfg.detectsSource() {
+			response.Format = format
+		}
+		writeJSONResponse(w, r, http.StatusOK, response)
 		return
 	}
 
 	writeSuccessResponse(w, result.Value, hasNullTerm(r))
 }
 
+func newConversionHandler(cfg conversionHandlerConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		handleConversion(w, r, cfg)
+	}
+}
+
+func handleConvert(w http.ResponseWriter, r *http.Request) {
+	lang := getLang(r)
+	query := r.URL.Query()
+	from := query.Get("from")
+	to := query.Get("to")
+	targetsParam := query.Get("targets")
+	if from == "" || (to == "" && targetsParam == "") {
+		writeErrorResponse(w, r, ErrorMissingParameter, msg("convert_params_required", lang), "from/to query parameters missing")
+		return
+	}
+
+	if targetsParam != "" {
+		targets, pairErr, message := resolveConversionTargets(from, targetsParam, lang)
+		if !pairErr.IsValid() {
+			writeErrorResponse(w, r, pairErr, message, from+"->"+targetsParam)
+			return
+		}
+		handleMultiTargetConversion(w, r, targets)
+		return
+	}
+
+	cfg, pairErr, message := resolveConversionPair(from, to, lang)
+	if !pairErr.IsValid() {
+		writeErrorResponse(w, r, pairErr, message, from+"->"+to)
+		return
+	}
+
+	handleConversion(w, r, cfg)
+}
+
+func handleDescribe(w http.ResponseWriter, r *http.Request) {
+	lang := getLang(r)
+	logDebug(r, "%s request: %v", describeConfig.RequestLabel, r.URL.RawQuery)
+
+	steamid := strings.TrimSpace(r.URL.Query().Get("steamid"))
+	if steamid == "" {
+		writeErrorResponse(w, r, ErrorMissingParameter, msg("steamid_param_required", lang), "steamid query parameter missing")
+		return
+	}
+
+	opts, optsErr := resolveConversionOptions(r, describeConfig)
+	if !optsErr.IsValid() {
+		writeErrorResponse(w, r, optsErr, "", "sid2 universe override rejected")
+		return
+	}
+
+	description, result := describeSteamID(r.Context(), steamid, lang, opts)
+	if !result.Error.IsValid() {
+		writeItemErrorResponse(w, r, result.Error, "", result.ErrorContext, errorItem{Input: steamid, Index: -1})
+		return
+	}
+
+	if wantsJSON(r) {
+		writeJSONResponse(w, r, http.StatusOK, description)
+		return
+	}
+
+	writeKeyValueResponse(w, formatDescriptionAsKeyValue(description, "SteamIDTools"), hasNullTerm(r))
+}
+
 func handleSteamID64ToAccountID(w http.ResponseWriter, r *http.Request) {
 	handleConversion(w, r, sid64ToAIDConfig)
 }
@@ -207,21 +556,35 @@ func handleSteamID64ToSteamID3(w http.ResponseWriter, r *http.Request) {
 }
 
 func handleNotFound(w http.ResponseWriter, r *http.Request) {
+	appWarnf("invalid endpoint requested: path=%s remote_addr=%s", r.URL.Path, r.RemoteAddr)
+	errorMsg := fmt.Sprintf("Invalid endpoint. Available endpoints: %s", strings.Join(availableEndpoints(), ", "))
+	if wantsJSON(r) || wantsKeyValueBinary(r) {
+		writeErrorResponse(w, r, ErrorInvalidEndpoint, errorMsg, r.URL.Path)
+		return
+	}
+
+	w.Header().Set(ErrorHeader, ErrorInvalidEndpoint.Key())
 	w.Header().Set("Content-Type", "text/plain")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
+	setCORSHeader(w)
 	w.WriteHeader(http.StatusNotFound)
-	errorMsg := fmt.Sprintf("Invalid endpoint. Available endpoints: %s, %s, %s, %s, %s, %s, %s", EndpointSID64toAID, EndpointSID64toSID2, EndpointSID64toSID3, EndpointAIDtoSID64, EndpointSID2toSID64, EndpointSID3toSID64, EndpointHealth)
 	writePlainTextBody(w, errorMsg+"\n")
-	appWarnf("invalid endpoint requested: path=%s remote_addr=%s", r.URL.Path, r.RemoteAddr)
 }
 
 func handleHealth(w http.ResponseWriter, r *http.Request) {
+	if serviceDrain.draining.Load() {
+		writeUnhealthy(w, r, ErrorServiceUnavailable, "UNHEALTHY: Shutting down\n")
+		return
+	}
+
 	testResult := AIDFromSID64("76561198008295809")
 	if !testResult.Error.IsValid() {
-		w.Header().Set("Content-Type", "text/plain")
-		w.WriteHeader(http.StatusServiceUnavailable)
-		writePlainTextBody(w, "UNHEALTHY: Conversion test failed\n")
 		appErrorf("health check failed: code=%s remote_addr=%s", testResult.Error.Key(), r.RemoteAddr)
+		writeUnhealthy(w, r, testResult.Error, "UNHEALTHY: Conversion test failed\n")
+		return
+	}
+	metrics.countHealthCheck("healthy")
+	if wantsJSON(r) {
+		writeJSONResponse(w, r, http.StatusOK, HealthResponse{Status: "healthy"})
 		return
 	}
 	w.Header().Set("Content-Type", "text/plain")
@@ -229,6 +592,20 @@ func handleHealth(w http.ResponseWriter, r *http.Request) {
 	writePlainTextBody(w, "HEALTHY\n")
 }
 
+// writeUnhealthy answers a failed health check with 503, which SourceMod
+// reports as SteamIDToolsBackendStatus_Offline.
+func writeUnhealthy(w http.ResponseWriter, r *http.Request, err SteamIDError, body string) {
+	metrics.countHealthCheck("unhealthy")
+	w.Header().Set(ErrorHeader, err.Key())
+	if wantsJSON(r) {
+		writeJSONResponse(w, r, http.StatusServiceUnavailable, HealthResponse{Status: "unhealthy", Error: err.Key()})
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusServiceUnavailable)
+	writePlainTextBody(w, body)
+}
+
 func handleAccountIDToSteamID64(w http.ResponseWriter, r *http.Request) {
 	handleConversion(w, r, aidToSID64Config)
 }
@@ -241,101 +618,503 @@ func handleSteamID3ToSteamID64(w http.ResponseWriter, r *http.Request) {
 	handleConversion(w, r, sid3ToSID64Config)
 }
 
+func handleGroupID64ToClanID(w http.ResponseWriter, r *http.Request) {
+	handleConversion(w, r, gid64ToClanIDConfig)
+}
+
+func handleGroupID64ToGroupID3(w http.ResponseWriter, r *http.Request) {
+	handleConversion(w, r, gid64ToGID3Config)
+}
+
+func handleClanIDToGroupID64(w http.ResponseWriter, r *http.Request) {
+	handleConversion(w, r, clanIDToGID64Config)
+}
+
+func handleClanIDToGroupID3(w http.ResponseWriter, r *http.Request) {
+	handleConversion(w, r, clanIDToGID3Config)
+}
+
+func handleGroupID3ToGroupID64(w http.ResponseWriter, r *http.Request) {
+	handleConversion(w, r, gid3ToGID64Config)
+}
+
+func handleGroupID3ToClanID(w http.ResponseWriter, r *http.Request) {
+	handleConversion(w, r, gid3ToClanIDConfig)
+}
+
 // HandleSteamID64ToAccountID godoc
 // @Summary Convert SteamID64 to AccountID
-// @Description Converts one SteamID64 value to AccountID. Supports comma-separated batch input via the steamid query parameter.
+// @Description Converts one SteamID64 value to AccountID. Supports comma-separated batch input via the steamid query parameter, or a POST body (newline-delimited text, JSON array or Valve KeyValue section) for larger batches.
 // @Tags conversion
+// @Accept plain
+// @Accept json
 // @Produce plain
+// @Produce json
+// @Produce octet-stream
 // @Param steamid query string true "SteamID64 value or comma-separated SteamID64 batch"
+// @Param format query string false "Set to json for {input, value} or, in batch, an array of items with error code and localized message; kvbinary for binary Valve KeyValues with numeric error codes (for HTTP clients; the SourceMod plugin transports cannot read it)"
 // @Param nullterm query int false "Append a NUL terminator to the plain-text response"
+// @Param mode query string false "Batch mode: reject (default) fails on duplicates, dedupe collapses them, positional keys every slot by index; naming a mode appends a summary block" Enums(reject, dedupe, positional)
+// @Param identity query string false "Set to account to detect duplicates by account across formats (STEAM_0/STEAM_1/[U:1:N]); batch items then report their canonical account and merged aliases" Enums(raw, account)
+// @Param page_size query int false "Maximum batch items per page; the response ends with a cursor key while more pages remain"
+// @Param max_bytes query int false "Client buffer size per page in bytes, counting the NUL terminator, such as 4096 for STEAMIDTOOLS_MAX_RESULT_LENGTH"
+// @Param cursor query string false "Cursor from the previous page; send the same batch again with it to get the next page"
+// @Param async query int false "Set to 1 (or send Prefer: respond-async) to run a batch as a background job; the response holds the job ID to poll at /jobs/{id}"
 // @Success 200 {string} string "Converted AccountID or Valve KeyValue batch response"
 // @Failure 400 {string} string "Validation error"
 // @Failure 503 {string} string "Service unavailable"
+// @Failure 413 {string} string "POST body exceeds MAX_BATCH_BODY_BYTES"
 // @Router /SID64toAID [get]
+// @Router /SID64toAID [post]
 func HandleSteamID64ToAccountID(w http.ResponseWriter, r *http.Request) {
 	handleSteamID64ToAccountID(w, r)
 }
 
 // HandleSteamID64ToSteamID2 godoc
 // @Summary Convert SteamID64 to SteamID2
-// @Description Converts one SteamID64 value to SteamID2. Supports comma-separated batch input via the steamid query parameter.
+// @Description Converts one SteamID64 value to SteamID2. Supports comma-separated batch input via the steamid query parameter, or a POST body (newline-delimited text, JSON array or Valve KeyValue section) for larger batches.
 // @Tags conversion
+// @Accept plain
+// @Accept json
 // @Produce plain
+// @Produce json
+// @Produce octet-stream
 // @Param steamid query string true "SteamID64 value or comma-separated SteamID64 batch"
+// @Param format query string false "Set to json for {input, value} or, in batch, an array of items with error code and localized message; kvbinary for binary Valve KeyValues with numeric error codes (for HTTP clients; the SourceMod plugin transports cannot read it)"
 // @Param nullterm query int false "Append a NUL terminator to the plain-text response"
+// @Param mode query string false "Batch mode: reject (default) fails on duplicates, dedupe collapses them, positional keys every slot by index; naming a mode appends a summary block" Enums(reject, dedupe, positional)
+// @Param identity query string false "Set to account to detect duplicates by account across formats (STEAM_0/STEAM_1/[U:1:N]); batch items then report their canonical account and merged aliases" Enums(raw, account)
+// @Param page_size query int false "Maximum batch items per page; the response ends with a cursor key while more pages remain"
+// @Param max_bytes query int false "Client buffer size per page in bytes, counting the NUL terminator, such as 4096 for STEAMIDTOOLS_MAX_RESULT_LENGTH"
+// @Param cursor query string false "Cursor from the previous page; send the same batch again with it to get the next page"
+// @Param async query int false "Set to 1 (or send Prefer: respond-async) to run a batch as a background job; the response holds the job ID to poll at /jobs/{id}"
+// @Param universe query string false "SteamID2 universe override for this request (0 or 1)"
+// @Param X-SteamIDTools-SID2-Universe header string false "SteamID2 universe override when the universe query parameter is absent (0 or 1)"
 // @Success 200 {string} string "Converted SteamID2 or Valve KeyValue batch response"
 // @Failure 400 {string} string "Validation error"
 // @Failure 503 {string} string "Service unavailable"
+// @Failure 413 {string} string "POST body exceeds MAX_BATCH_BODY_BYTES"
 // @Router /SID64toSID2 [get]
+// @Router /SID64toSID2 [post]
 func HandleSteamID64ToSteamID2(w http.ResponseWriter, r *http.Request) {
 	handleSteamID64ToSteamID2(w, r)
 }
 
 // HandleSteamID64ToSteamID3 godoc
 // @Summary Convert SteamID64 to SteamID3
-// @Description Converts one SteamID64 value to SteamID3. Supports comma-separated batch input via the steamid query parameter.
+// @Description Converts one SteamID64 value to SteamID3. Supports comma-separated batch input via the steamid query parameter, or a POST body (newline-delimited text, JSON array or Valve KeyValue section) for larger batches.
 // @Tags conversion
+// @Accept plain
+// @Accept json
 // @Produce plain
+// @Produce json
+// @Produce octet-stream
 // @Param steamid query string true "SteamID64 value or comma-separated SteamID64 batch"
+// @Param format query string false "Set to json for {input, value} or, in batch, an array of items with error code and localized message; kvbinary for binary Valve KeyValues with numeric error codes (for HTTP clients; the SourceMod plugin transports cannot read it)"
 // @Param nullterm query int false "Append a NUL terminator to the plain-text response"
+// @Param mode query string false "Batch mode: reject (default) fails on duplicates, dedupe collapses them, positional keys every slot by index; naming a mode appends a summary block" Enums(reject, dedupe, positional)
+// @Param identity query string false "Set to account to detect duplicates by account across formats (STEAM_0/STEAM_1/[U:1:N]); batch items then report their canonical account and merged aliases" Enums(raw, account)
+// @Param page_size query int false "Maximum batch items per page; the response ends with a cursor key while more pages remain"
+// @Param max_bytes query int false "Client buffer size per page in bytes, counting the NUL terminator, such as 4096 for STEAMIDTOOLS_MAX_RESULT_LENGTH"
+// @Param cursor query string false "Cursor from the previous page; send the same batch again with it to get the next page"
+// @Param async query int false "Set to 1 (or send Prefer: respond-async) to run a batch as a background job; the response holds the job ID to poll at /jobs/{id}"
 // @Success 200 {string} string "Converted SteamID3 or Valve KeyValue batch response"
 // @Failure 400 {string} string "Validation error"
 // @Failure 503 {string} string "Service unavailable"
+// @Failure 413 {string} string "POST body exceeds MAX_BATCH_BODY_BYTES"
 // @Router /SID64toSID3 [get]
+// @Router /SID64toSID3 [post]
 func HandleSteamID64ToSteamID3(w http.ResponseWriter, r *http.Request) {
 	handleSteamID64ToSteamID3(w, r)
 }
 
 // HandleAccountIDToSteamID64 godoc
 // @Summary Convert AccountID to SteamID64
-// @Description Converts one AccountID value to SteamID64. Supports comma-separated batch input via the steamid query parameter.
+// @Description Converts one AccountID value to SteamID64. Supports comma-separated batch input via the steamid query parameter, or a POST body (newline-delimited text, JSON array or Valve KeyValue section) for larger batches.
 // @Tags conversion
+// @Accept plain
+// @Accept json
 // @Produce plain
+// @Produce json
+// @Produce octet-stream
 // @Param steamid query string true "AccountID value or comma-separated AccountID batch"
+// @Param format query string false "Set to json for {input, value} or, in batch, an array of items with error code and localized message; kvbinary for binary Valve KeyValues with numeric error codes (for HTTP clients; the SourceMod plugin transports cannot read it)"
 // @Param nullterm query int false "Append a NUL terminator to the plain-text response"
+// @Param mode query string false "Batch mode: reject (default) fails on duplicates, dedupe collapses them, positional keys every slot by index; naming a mode appends a summary block" Enums(reject, dedupe, positional)
+// @Param identity query string false "Set to account to detect duplicates by account across formats (STEAM_0/STEAM_1/[U:1:N]); batch items then report their canonical account and merged aliases" Enums(raw, account)
+// @Param page_size query int false "Maximum batch items per page; the response ends with a cursor key while more pages remain"
+// @Param max_bytes query int false "Client buffer size per page in bytes, counting the NUL terminator, such as 4096 for STEAMIDTOOLS_MAX_RESULT_LENGTH"
+// @Param cursor query string false "Cursor from the previous page; send the same batch again with it to get the next page"
+// @Param async query int false "Set to 1 (or send Prefer: respond-async) to run a batch as a background job; the response holds the job ID to poll at /jobs/{id}"
 // @Success 200 {string} string "Converted SteamID64 or Valve KeyValue batch response"
 // @Failure 400 {string} string "Validation error"
 // @Failure 503 {string} string "Service unavailable"
+// @Failure 413 {string} string "POST body exceeds MAX_BATCH_BODY_BYTES"
 // @Router /AIDtoSID64 [get]
+// @Router /AIDtoSID64 [post]
 func HandleAccountIDToSteamID64(w http.ResponseWriter, r *http.Request) {
 	handleAccountIDToSteamID64(w, r)
 }
 
 // HandleSteamID2ToSteamID64 godoc
 // @Summary Convert SteamID2 to SteamID64
-// @Description Converts one SteamID2 value to SteamID64. Supports comma-separated batch input via the steamid query parameter.
+// @Description Converts one SteamID2 value to SteamID64. Supports comma-separated batch input via the steamid query parameter, or a POST body (newline-delimited text, JSON array or Valve KeyValue section) for larger batches.
 // @Tags conversion
+// @Accept plain
+// @Accept json
 // @Produce plain
+// @Produce json
+// @Produce octet-stream
 // @Param steamid query string true "SteamID2 value or comma-separated SteamID2 batch"
+// @Param format query string false "Set to json for {input, value} or, in batch, an array of items with error code and localized message; kvbinary for binary Valve KeyValues with numeric error codes (for HTTP clients; the SourceMod plugin transports cannot read it)"
 // @Param nullterm query int false "Append a NUL terminator to the plain-text response"
+// @Param mode query string false "Batch mode: reject (default) fails on duplicates, dedupe collapses them, positional keys every slot by index; naming a mode appends a summary block" Enums(reject, dedupe, positional)
+// @Param identity query string false "Set to account to detect duplicates by account across formats (STEAM_0/STEAM_1/[U:1:N]); batch items then report their canonical account and merged aliases" Enums(raw, account)
+// @Param page_size query int false "Maximum batch items per page; the response ends with a cursor key while more pages remain"
+// @Param max_bytes query int false "Client buffer size per page in bytes, counting the NUL terminator, such as 4096 for STEAMIDTOOLS_MAX_RESULT_LENGTH"
+// @Param cursor query string false "Cursor from the previous page; send the same batch again with it to get the next page"
+// @Param async query int false "Set to 1 (or send Prefer: respond-async) to run a batch as a background job; the response holds the job ID to poll at /jobs/{id}"
 // @Success 200 {string} string "Converted SteamID64 or Valve KeyValue batch response"
 // @Failure 400 {string} string "Validation error"
 // @Failure 503 {string} string "Service unavailable"
+// @Failure 413 {string} string "POST body exceeds MAX_BATCH_BODY_BYTES"
 // @Router /SID2toSID64 [get]
+// @Router /SID2toSID64 [post]
 func HandleSteamID2ToSteamID64(w http.ResponseWriter, r *http.Request) {
 	handleSteamID2ToSteamID64(w, r)
 }
 
 // HandleSteamID3ToSteamID64 godoc
 // @Summary Convert SteamID3 to SteamID64
-// @Description Converts one SteamID3 value to SteamID64. Supports comma-separated batch input via the steamid query parameter.
+// @Description Converts one SteamID3 value to SteamID64. Supports comma-separated batch input via the steamid query parameter, or a POST body (newline-delimited text, JSON array or Valve KeyValue section) for larger batches.
 // @Tags conversion
+// @Accept plain
+// @Accept json
 // @Produce plain
+// @Produce json
+// @Produce octet-stream
 // @Param steamid query string true "SteamID3 value or comma-separated SteamID3 batch"
+// @Param format query string false "Set to json for {input, value} or, in batch, an array of items with error code and localized message; kvbinary for binary Valve KeyValues with numeric error codes (for HTTP clients; the SourceMod plugin transports cannot read it)"
 // @Param nullterm query int false "Append a NUL terminator to the plain-text response"
+// @Param mode query string false "Batch mode: reject (default) fails on duplicates, dedupe collapses them, positional keys every slot by index; naming a mode appends a summary block" Enums(reject, dedupe, positional)
+// @Param identity query string false "Set to account to detect duplicates by account across formats (STEAM_0/STEAM_1/[U:1:N]); batch items then report their canonical account and merged aliases" Enums(raw, account)
+// @Param page_size query int false "Maximum batch items per page; the response ends with a cursor key while more pages remain"
+// @Param max_bytes query int false "Client buffer size per page in bytes, counting the NUL terminator, such as 4096 for STEAMIDTOOLS_MAX_RESULT_LENGTH"
+// @Param cursor query string false "Cursor from the previous page; send the same batch again with it to get the next page"
+// @Param async query int false "Set to 1 (or send Prefer: respond-async) to run a batch as a background job; the response holds the job ID to poll at /jobs/{id}"
 // @Success 200 {string} string "Converted SteamID64 or Valve KeyValue batch response"
 // @Failure 400 {string} string "Validation error"
 // @Failure 503 {string} string "Service unavailable"
+// @Failure 413 {string} string "POST body exceeds MAX_BATCH_BODY_BYTES"
 // @Router /SID3toSID64 [get]
+// @Router /SID3toSID64 [post]
 func HandleSteamID3ToSteamID64(w http.ResponseWriter, r *http.Request) {
 	handleSteamID3ToSteamID64(w, r)
 }
 
+// HandleGroupID64ToClanID godoc
+// @Summary Convert group SteamID64 to clan AccountID
+// @Description Converts one group SteamID64 value to clan AccountID. Supports comma-separated batch input via the steamid query parameter, or a POST body (newline-delimited text, JSON array or Valve KeyValue section) for larger batches.
+// @Tags groups
+// @Produce plain
+// @Produce json
+// @Produce octet-stream
+// @Param steamid query string true "Steam group SteamID64 value or comma-separated group SteamID64 batch"
+// @Param format query string false "Set to json for {input, value} or, in batch, an array of items with error code and localized message; kvbinary for binary Valve KeyValues with numeric error codes (for HTTP clients; the SourceMod plugin transports cannot read it)"
+// @Param nullterm query int false "Append a NUL terminator to the plain-text response"
+// @Param mode query string false "Batch mode: reject (default) fails on duplicates, dedupe collapses them, positional keys every slot by index; naming a mode appends a summary block" Enums(reject, dedupe, positional)
+// @Param identity query string false "Set to account to detect duplicates by account across formats (STEAM_0/STEAM_1/[U:1:N]); batch items then report their canonical account and merged aliases" Enums(raw, account)
+// @Param page_size query int false "Maximum batch items per page; the response ends with a cursor key while more pages remain"
+// @Param max_bytes query int false "Client buffer size per page in bytes, counting the NUL terminator, such as 4096 for STEAMIDTOOLS_MAX_RESULT_LENGTH"
+// @Param cursor query string false "Cursor from the previous page; send the same batch again with it to get the next page"
+// @Param async query int false "Set to 1 (or send Prefer: respond-async) to run a batch as a background job; the response holds the job ID to poll at /jobs/{id}"
+// @Success 200 {string} string "Converted clan AccountID or Valve KeyValue batch response"
+// @Failure 400 {string} string "Validation error"
+// @Failure 503 {string} string "Service unavailable"
+// @Failure 413 {string} string "POST body exceeds MAX_BATCH_BODY_BYTES"
+// @Router /GID64toClanID [get]
+// @Router /GID64toClanID [post]
+func HandleGroupID64ToClanID(w http.ResponseWriter, r *http.Request) {
+	handleGroupID64ToClanID(w, r)
+}
+
+// HandleGroupID64ToGroupID3 godoc
+// @Summary Convert group SteamID64 to group SteamID3
+// @Description Converts one group SteamID64 value to group SteamID3. Supports comma-separated batch input via the steamid query parameter, or a POST body (newline-delimited text, JSON array or Valve KeyValue section) for larger batches.
+// @Tags groups
+// @Produce plain
+// @Produce json
+// @Produce octet-stream
+// @Param steamid query string true "Steam group SteamID64 value or comma-separated group SteamID64 batch"
+// @Param format query string false "Set to json for {input, value} or, in batch, an array of items with error code and localized message; kvbinary for binary Valve KeyValues with numeric error codes (for HTTP clients; the SourceMod plugin transports cannot read it)"
+// @Param nullterm query int false "Append a NUL terminator to the plain-text response"
+// @Param mode query string false "Batch mode: reject (default) fails on duplicates, dedupe collapses them, positional keys every slot by index; naming a mode appends a summary block" Enums(reject, dedupe, positional)
+// @Param identity query string false "Set to account to detect duplicates by account across formats (STEAM_0/STEAM_1/[U:1:N]); batch items then report their canonical account and merged aliases" Enums(raw, account)
+// @Param page_size query int false "Maximum batch items per page; the response ends with a cursor key while more pages remain"
+// @Param max_bytes query int false "Client buffer size per page in bytes, counting the NUL terminator, such as 4096 for STEAMIDTOOLS_MAX_RESULT_LENGTH"
+// @Param cursor query string false "Cursor from the previous page; send the same batch again with it to get the next page"
+// @Param async query int false "Set to 1 (or send Prefer: respond-async) to run a batch as a background job; the response holds the job ID to poll at /jobs/{id}"
+// @Success 200 {string} string "Converted group SteamID3 or Valve KeyValue batch response"
+// @Failure 400 {string} string "Validation error"
+// @Failure 503 {string} string "Service unavailable"
+// @Failure 413 {string} string "POST body exceeds MAX_BATCH_BODY_BYTES"
+// @Router /GID64toGID3 [get]
+// @Router /GID64toGID3 [post]
+func HandleGroupID64ToGroupID3(w http.ResponseWriter, r *http.Request) {
+	handleGroupID64ToGroupID3(w, r)
+}
+
+// HandleClanIDToGroupID64 godoc
+// @Summary Convert clan AccountID to group SteamID64
+// @Description Converts one clan AccountID value to group SteamID64. Supports comma-separated batch input via the steamid query parameter, or a POST body (newline-delimited text, JSON array or Valve KeyValue section) for larger batches.
+// @Tags groups
+// @Produce plain
+// @Produce json
+// @Produce octet-stream
+// @Param steamid query string true "Clan AccountID value or comma-separated clan AccountID batch"
+// @Param format query string false "Set to json for {input, value} or, in batch, an array of items with error code and localized message; kvbinary for binary Valve KeyValues with numeric error codes (for HTTP clients; the SourceMod plugin transports cannot read it)"
+// @Param nullterm query int false "Append a NUL terminator to the plain-text response"
+// @Param mode query string false "Batch mode: reject (default) fails on duplicates, dedupe collapses them, positional keys every slot by index; naming a mode appends a summary block" Enums(reject, dedupe, positional)
+// @Param identity query string false "Set to account to detect duplicates by account across formats (STEAM_0/STEAM_1/[U:1:N]); batch items then report their canonical account and merged aliases" Enums(raw, account)
+// @Param page_size query int false "Maximum batch items per page; the response ends with a cursor key while more pages remain"
+// @Param max_bytes query int false "Client buffer size per page in bytes, counting the NUL terminator, such as 4096 for STEAMIDTOOLS_MAX_RESULT_LENGTH"
+// @Param cursor query string false "Cursor from the previous page; send the same batch again with it to get the next page"
+// @Param async query int false "Set to 1 (or send Prefer: respond-async) to run a batch as a background job; the response holds the job ID to poll at /jobs/{id}"
+// @Success 200 {string} string "Converted group SteamID64 or Valve KeyValue batch response"
+// @Failure 400 {string} string "Validation error"
+// @Failure 503 {string} string "Service unavailable"
+// @Failure 413 {string} string "POST body exceeds MAX_BATCH_BODY_BYTES"
+// @Router /ClanIDtoGID64 [get]
+// @Router /ClanIDtoGID64 [post]
+func HandleClanIDToGroupID64(w http.ResponseWriter, r *http.Request) {
+	handleClanIDToGroupID64(w, r)
+}
+
+// HandleClanIDToGroupID3 godoc
+// @Summary Convert clan AccountID to group SteamID3
+// @Description Converts one clan AccountID value to group SteamID3. Supports comma-separated batch input via the steamid query parameter, or a POST body (newline-delimited text, JSON array or Valve KeyValue section) for larger batches.
+// @Tags groups
+// @Produce plain
+// @Produce json
+// @Produce octet-stream
+// @Param steamid query string true "Clan AccountID value or comma-separated clan AccountID batch"
+// @Param format query string false "Set to json for {input, value} or, in batch, an array of items with error code and localized message; kvbinary for binary Valve KeyValues with numeric error codes (for HTTP clients; the SourceMod plugin transports cannot read it)"
+// @Param nullterm query int false "Append a NUL terminator to the plain-text response"
+// @Param mode query string false "Batch mode: reject (default) fails on duplicates, dedupe collapses them, positional keys every slot by index; naming a mode appends a summary block" Enums(reject, dedupe, positional)
+// @Param identity query string false "Set to account to detect duplicates by account across formats (STEAM_0/STEAM_1/[U:1:N]); batch items then report their canonical account and merged aliases" Enums(raw, account)
+// @Param page_size query int false "Maximum batch items per page; the response ends with a cursor key while more pages remain"
+// @Param max_bytes query int false "Client buffer size per page in bytes, counting the NUL terminator, such as 4096 for STEAMIDTOOLS_MAX_RESULT_LENGTH"
+// @Param cursor query string false "Cursor from the previous page; send the same batch again with it to get the next page"
+// @Param async query int false "Set to 1 (or send Prefer: respond-async) to run a batch as a background job; the response holds the job ID to poll at /jobs/{id}"
+// @Success 200 {string} string "Converted group SteamID3 or Valve KeyValue batch response"
+// @Failure 400 {string} string "Validation error"
+// @Failure 503 {string} string "Service unavailable"
+// @Failure 413 {string} string "POST body exceeds MAX_BATCH_BODY_BYTES"
+// @Router /ClanIDtoGID3 [get]
+// @Router /ClanIDtoGID3 [post]
+func HandleClanIDToGroupID3(w http.ResponseWriter, r *http.Request) {
+	handleClanIDToGroupID3(w, r)
+}
+
+// HandleGroupID3ToGroupID64 godoc
+// @Summary Convert group SteamID3 to group SteamID64
+// @Description Converts one group SteamID3 value to group SteamID64. Supports comma-separated batch input via the steamid query parameter, or a POST body (newline-delimited text, JSON array or Valve KeyValue section) for larger batches.
+// @Tags groups
+// @Produce plain
+// @Produce json
+// @Produce octet-stream
+// @Param steamid query string true "Group SteamID3 value or comma-separated group SteamID3 batch"
+// @Param format query string false "Set to json for {input, value} or, in batch, an array of items with error code and localized message; kvbinary for binary Valve KeyValues with numeric error codes (for HTTP clients; the SourceMod plugin transports cannot read it)"
+// @Param nullterm query int false "Append a NUL terminator to the plain-text response"
+// @Param mode query string false "Batch mode: reject (default) fails on duplicates, dedupe collapses them, positional keys every slot by index; naming a mode appends a summary block" Enums(reject, dedupe, positional)
+// @Param identity query string false "Set to account to detect duplicates by account across formats (STEAM_0/STEAM_1/[U:1:N]); batch items then report their canonical account and merged aliases" Enums(raw, account)
+// @Param page_size query int false "Maximum batch items per page; the response ends with a cursor key while more pages remain"
+// @Param max_bytes query int false "Client buffer size per page in bytes, counting the NUL terminator, such as 4096 for STEAMIDTOOLS_MAX_RESULT_LENGTH"
+// @Param cursor query string false "Cursor from the previous page; send the same batch again with it to get the next page"
+// @Param async query int false "Set to 1 (or send Prefer: respond-async) to run a batch as a background job; the response holds the job ID to poll at /jobs/{id}"
+// @Success 200 {string} string "Converted group SteamID64 or Valve KeyValue batch response"
+// @Failure 400 {string} string "Validation error"
+// @Failure 503 {string} string "Service unavailable"
+// @Failure 413 {string} string "POST body exceeds MAX_BATCH_BODY_BYTES"
+// @Router /GID3toGID64 [get]
+// @Router /GID3toGID64 [post]
+func HandleGroupID3ToGroupID64(w http.ResponseWriter, r *http.Request) {
+	handleGroupID3ToGroupID64(w, r)
+}
+
+// HandleGroupID3ToClanID godoc
+// @Summary Convert group SteamID3 to clan AccountID
+// @Description Converts one group SteamID3 value to clan AccountID. Supports comma-separated batch input via the steamid query parameter, or a POST body (newline-delimited text, JSON array or Valve KeyValue section) for larger batches.
+// @Tags groups
+// @Produce plain
+// @Produce json
+// @Produce octet-stream
+// @Param steamid query string true "Group SteamID3 value or comma-separated group SteamID3 batch"
+// @Param format query string false "Set to json for {input, value} or, in batch, an array of items with error code and localized message; kvbinary for binary Valve KeyValues with numeric error codes (for HTTP clients; the SourceMod plugin transports cannot read it)"
+// @Param nullterm query int false "Append a NUL terminator to the plain-text response"
+// @Param mode query string false "Batch mode: reject (default) fails on duplicates, dedupe collapses them, positional keys every slot by index; naming a mode appends a summary block" Enums(reject, dedupe, positional)
+// @Param identity query string false "Set to account to detect duplicates by account across formats (STEAM_0/STEAM_1/[U:1:N]); batch items then report their canonical account and merged aliases" Enums(raw, account)
+// @Param page_size query int false "Maximum batch items per page; the response ends with a cursor key while more pages remain"
+// @Param max_bytes query int false "Client buffer size per page in bytes, counting the NUL terminator, such as 4096 for STEAMIDTOOLS_MAX_RESULT_LENGTH"
+// @Param cursor query string false "Cursor from the previous page; send the same batch again with it to get the next page"
+// @Param async query int false "Set to 1 (or send Prefer: respond-async) to run a batch as a background job; the response holds the job ID to poll at /jobs/{id}"
+// @Success 200 {string} string "Converted clan AccountID or Valve KeyValue batch response"
+// @Failure 400 {string} string "Validation error"
+// @Failure 503 {string} string "Service unavailable"
+// @Failure 413 {string} string "POST body exceeds MAX_BATCH_BODY_BYTES"
+// @Router /GID3toClanID [get]
+// @Router /GID3toClanID [post]
+func HandleGroupID3ToClanID(w http.ResponseWriter, r *http.Request) {
+	handleGroupID3ToClanID(w, r)
+}
+
+// HandleConvert godoc
+// @Summary Convert between any two formats
+// @Description Converts between any registered pair of formats. Account formats (sid64, aid, sid2, sid3, hex, url) convert among themselves, and group formats (gid64, clanid, gid3) convert among themselves. Every pair is also served as a fixed route such as /SID2toSID3. With from=auto each input's format is detected on its own, so one batch may mix formats; the batch response then reports the detected format per item. Supports comma-separated batch input via the steamid query parameter, or a POST body (newline-delimited text, JSON array or Valve KeyValue section) for larger batches.
+// @Tags conversion
+// @Accept plain
+// @Accept json
+// @Produce plain
+// @Produce json
+// @Produce octet-stream
+// @Param from query string true "Source format, or auto to detect it per input" Enums(auto, sid64, aid, sid2, sid3, hex, url, gid64, clanid, gid3)
+// @Param to query string false "Target format; required unless targets is set" Enums(sid64, aid, sid2, sid3, hex, url, gid64, clanid, gid3)
+// @Param targets query string false "Comma-separated target formats, such as sid2,sid3; returns one section (or JSON object) per input with one key per target and takes precedence over to"
+// @Param steamid query string true "Value or comma-separated batch in the source format"
+// @Param format query string false "Set to json for {input, value} or, in batch, an array of items with error code and localized message; kvbinary for binary Valve KeyValues with numeric error codes (for HTTP clients; the SourceMod plugin transports cannot read it)"
+// @Param nullterm query int false "Append a NUL terminator to the plain-text response"
+// @Param mode query string false "Batch mode: reject (default) fails on duplicates, dedupe collapses them, positional keys every slot by index; naming a mode appends a summary block" Enums(reject, dedupe, positional)
+// @Param identity query string false "Set to account to detect duplicates by account across formats (STEAM_0/STEAM_1/[U:1:N]); batch items then report their canonical account and merged aliases" Enums(raw, account)
+// @Param page_size query int false "Maximum batch items per page; the response ends with a cursor key while more pages remain"
+// @Param max_bytes query int false "Client buffer size per page in bytes, counting the NUL terminator, such as 4096 for STEAMIDTOOLS_MAX_RESULT_LENGTH"
+// @Param cursor query string false "Cursor from the previous page; send the same batch again with it to get the next page"
+// @Param async query int false "Set to 1 (or send Prefer: respond-async) to run a batch as a background job; the response holds the job ID to poll at /jobs/{id}"
+// @Param universe query string false "SteamID2 universe override when to=sid2 (0 or 1)"
+// @Success 200 {string} string "Converted value or Valve KeyValue batch response"
+// @Failure 400 {string} string "Validation error or unsupported conversion pair"
+// @Failure 413 {string} string "POST body exceeds MAX_BATCH_BODY_BYTES"
+// @Router /convert [get]
+// @Router /convert [post]
+func HandleConvert(w http.ResponseWriter, r *http.Request) {
+	handleConvert(w, r)
+}
+
+// HandleStream godoc
+// @Summary Stream a large batch as NDJSON
+// @Description Reads a POST body with one SteamID per line and writes one JSON object per line (input, format, value or error code and localized message) as each input is converted. The response is flushed periodically and the conversion stops when the client disconnects. Input size is not capped; lines longer than 4096 bytes end the stream with an error line. from defaults to auto.
+// @Tags conversion
+// @Accept plain
+// @Produce application/x-ndjson
+// @Param from query string false "Source format, or auto to detect it per input" Enums(auto, sid64, aid, sid2, sid3, hex, url, gid64, clanid, gid3)
+// @Param to query string true "Target format" Enums(sid64, aid, sid2, sid3, hex, url, gid64, clanid, gid3)
+// @Param universe query string false "SteamID2 universe override when to=sid2 (0 or 1)"
+// @Success 200 {string} string "NDJSON stream of batch items"
+// @Failure 400 {string} string "Validation error, unsupported conversion pair or missing POST body"
+// @Router /stream [post]
+func HandleStream(w http.ResponseWriter, r *http.Request) {
+	handleStream(w, r)
+}
+
+// HandleCSV godoc
+// @Summary Convert SteamID columns in a CSV file
+// @Description Reads a CSV POST body and returns it with the selected columns converted. mode=append (default) adds a <column>_<to> column per selected column; mode=replace rewrites the cells in place. Every selected column also gets a <column>_error column holding the error code of a failed cell, so bad rows are annotated instead of aborting the file. The output is streamed.
+// @Tags conversion
+// @Accept text/csv
+// @Produce text/csv
+// @Param columns query string true "Comma-separated header names or 1-based column indexes"
+// @Param to query string true "Target format" Enums(sid64, aid, sid2, sid3, hex, url, gid64, clanid, gid3)
+// @Param from query string false "Source format, or auto to detect it per cell" Enums(auto, sid64, aid, sid2, sid3, hex, url, gid64, clanid, gid3)
+// @Param mode query string false "append (default) or replace" Enums(append, replace)
+// @Param header query int false "Set to 0 when the first row is data; columns must then be indexes"
+// @Param delimiter query string false "Field delimiter, one character or tab (default ,)"
+// @Param universe query string false "SteamID2 universe override when to=sid2 (0 or 1)"
+// @Success 200 {string} string "Rewritten CSV"
+// @Failure 400 {string} string "Validation error, unknown column or unsupported conversion pair"
+// @Router /csv [post]
+func HandleCSV(w http.ResponseWriter, r *http.Request) {
+	handleCSV(w, r)
+}
+
+// HandleJob godoc
+// @Summary Poll an asynchronous batch job
+// @Description Returns the status of a batch submitted with async=1 while it is queued or running, then the batch response itself (status code, content type and body as the synchronous request would have returned them). The X-SteamIDTools-Job-Status header always carries the status. Jobs expire JOB_TTL after their last update. /jobs?steamid={id} is accepted as well so SteamIDTools_RequestConversion can poll with API_Jobs.
+// @Tags jobs
+// @Produce plain
+// @Produce json
+// @Produce octet-stream
+// @Param id path string true "Job ID returned on submission"
+// @Param format query string false "Set to json or kvbinary for the status document of an unfinished job"
+// @Param nullterm query int false "Append a NUL terminator to the KeyValue status document"
+// @Success 200 {string} string "Valve KeyValue job status or the finished batch response"
+// @Failure 404 {string} string "Job not found or expired"
+// @Router /jobs/{id} [get]
+func HandleJob(w http.ResponseWriter, r *http.Request) {
+	handleJob(w, r)
+}
+
+// HandleErrors godoc
+// @Summary List error codes
+// @Description Lists every error key with its numeric kvbinary code, the HTTP status of a request failing with it and its message in each supported language. Error responses carry the key in the X-SteamIDTools-Error header, and JSON errors are RFC 9457 problem documents whose type is /errors/{key}.
+// @Tags errors
+// @Produce plain
+// @Produce json
+// @Param format query string false "Set to json for a JSON catalog instead of Valve KeyValue"
+// @Success 200 {object} ErrorCatalogResponse "Valve KeyValue or JSON error catalog"
+// @Router /errors [get]
+func HandleErrors(w http.ResponseWriter, r *http.Request) {
+	handleErrors(w, r)
+}
+
+// HandleErrorKey godoc
+// @Summary Describe one error code
+// @Description Returns the catalog entry of one error key; this is the type URI of JSON problem responses.
+// @Tags errors
+// @Produce plain
+// @Produce json
+// @Param key path string true "Error key, for example invalid_steamid2"
+// @Param format query string false "Set to json for a JSON entry instead of Valve KeyValue"
+// @Success 200 {object} ErrorCatalogEntry "Valve KeyValue or JSON catalog entry"
+// @Failure 404 {object} ProblemResponse "Unknown error key"
+// @Router /errors/{key} [get]
+func HandleErrorKey(w http.ResponseWriter, r *http.Request) {
+	handleErrors(w, r)
+}
+
+// HandleDescribe godoc
+// @Summary Describe any SteamID
+// @Description Auto-detects SteamID64, SteamID2, SteamID3, AccountID, hex (steam:/0x) or steamcommunity.com profile URL input and returns every representation plus universe, type and instance.
+// @Tags conversion
+// @Produce plain
+// @Produce json
+// @Param steamid query string true "SteamID in any supported format"
+// @Param format query string false "Set to json for a JSON document instead of Valve KeyValue"
+// @Param universe query string false "SteamID2 universe used for the steamid2 key (0 or 1)"
+// @Param nullterm query int false "Append a NUL terminator to the KeyValue response"
+// @Success 200 {string} string "Valve KeyValue or JSON description"
+// @Failure 400 {string} string "Validation error"
+// @Router /describe [get]
+func HandleDescribe(w http.ResponseWriter, r *http.Request) {
+	handleDescribe(w, r)
+}
+
+// HandleMetrics godoc
+// @Summary Prometheus metrics
+// @Description Request counts and latency per endpoint, batch sizes, errors by key, health check outcomes and build info in the Prometheus text format. When METRICS_ADDR is set it is only served on that listener.
+// @Tags health
+// @Produce plain
+// @Success 200 {string} string "Prometheus text exposition format"
+// @Router /metrics [get]
+func HandleMetrics(w http.ResponseWriter, r *http.Request) {
+	handleMetrics(w, r)
+}
+
 // HandleHealth godoc
 // @Summary Health check
-// @Description Returns the backend health status after a self-check conversion.
+// @Description Returns the backend health status after a self-check conversion. Answers 503 once the service is shutting down.
 // @Tags health
 // @Produce plain
+// @Produce json
+// @Param format query string false "Set to json for {status} instead of plain text"
 // @Success 200 {string} string "HEALTHY"
 // @Failure 503 {string} string "UNHEALTHY"
 // @Router /health [get]
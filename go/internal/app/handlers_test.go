@@ -3,9 +3,12 @@ package app
 import (
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
+
+	"steamid-service/pkg/keyvalues"
 )
 
 func TestHandleSteamID64ToAccountIDMissingParameterLocalized(t *testing.T) {
@@ -71,6 +74,103 @@ func TestHandleSteamID64ToAccountIDBatchPreservesOrderAndLocalizesErrors(t *test
 	}
 }
 
+func TestHandleBatchKeyValueEscapesInputs(t *testing.T) {
+	body := "76561197960287930\nbad\"id\\\nline\tbreak"
+	req := httptest.NewRequest(http.MethodPost, EndpointSID64toAID, strings.NewReader(body))
+	req.Header.Set("Accept-Language", "en")
+	rec := httptest.NewRecorder()
+
+	HandleSteamID64ToAccountID(rec, req)
+
+	expected := "\"SteamIDTools\"\n{\n" +
+		"    \"76561197960287930\" \"22202\"\n" +
+		"    \"bad\\\"id\\\\\" \"ERROR: SteamID length is incorrect\"\n" +
+		"    \"line\\tbreak\" \"ERROR: SteamID length is incorrect\"\n" +
+		"}"
+	if got := rec.Body.String(); got != expected {
+		t.Fatalf("unexpected body:\n%s", got)
+	}
+
+	root, err := keyvalues.Unmarshal(rec.Body.Bytes())
+	if err != nil {
+		t.Fatalf("response is not valid KeyValues: %v", err)
+	}
+	if len(root.Children) != 3 || root.Children[1].Key != "bad\"id\\" {
+		t.Fatalf("unexpected decoded response %+v", root)
+	}
+}
+
+func TestHandleConversionKeyValueBinary(t *testing.T) {
+	t.Parallel()
+
+	decode := func(t *testing.T, rec *httptest.ResponseRecorder) keyvalues.Node {
+		t.Helper()
+		if ct := rec.Header().Get("Content-Type"); ct != "application/octet-stream" {
+			t.Fatalf("unexpected content type %q", ct)
+		}
+		root, err := keyvalues.UnmarshalBinary(rec.Body.Bytes())
+		if err != nil {
+			t.Fatalf("invalid binary KeyValues: %v", err)
+		}
+		return root
+	}
+
+	t.Run("batch", func(t *testing.T) {
+		t.Parallel()
+
+		req := httptest.NewRequest(http.MethodGet, EndpointSID64toAID+"?format=kvbinary&steamid=76561197960287930,123", nil)
+		req.Header.Set("Accept", "application/json")
+		rec := httptest.NewRecorder()
+		HandleSteamID64ToAccountID(rec, req)
+
+		root := decode(t, rec)
+		expected := keyvalues.NewSection("SteamIDTools",
+			keyvalues.NewSection("76561197960287930",
+				keyvalues.NewValue("value", "22202"),
+				keyvalues.NewInt("error", 0),
+			),
+			keyvalues.NewSection("123",
+				keyvalues.NewInt("error", ErrorInvalidLength.Code()),
+				keyvalues.NewValue("error_key", "invalid_length"),
+			),
+		)
+		if !reflect.DeepEqual(root, expected) {
+			t.Fatalf("unexpected document %+v", root)
+		}
+	})
+
+	t.Run("single", func(t *testing.T) {
+		t.Parallel()
+
+		req := httptest.NewRequest(http.MethodGet, EndpointConvert+"?from=auto&to=aid&format=kvbinary&steamid=[U:1:22202]", nil)
+		rec := httptest.NewRecorder()
+		HandleConvert(rec, req)
+
+		root := decode(t, rec)
+		if root.String("input") != "[U:1:22202]" || root.String("format") != "sid3" || root.String("value") != "22202" || root.String("error") != "0" {
+			t.Fatalf("unexpected document %+v", root)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		t.Parallel()
+
+		req := httptest.NewRequest(http.MethodGet, EndpointSID64toAID+"?format=kvbinary&steamid=123", nil)
+		req.Header.Set("Accept-Language", "en")
+		rec := httptest.NewRecorder()
+		HandleSteamID64ToAccountID(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+		}
+		root := decode(t, rec)
+		errorCode, _ := root.Get("error")
+		if errorCode.Kind != keyvalues.KindInt || errorCode.Value != "2" || root.String("error_key") != "invalid_length" {
+			t.Fatalf("unexpected document %+v", root)
+		}
+	})
+}
+
 func TestHandleSteamID64ToSteamID2UsesConfiguredUniverse(t *testing.T) {
 	previous := appCfg.SID2Universe
 	appCfg.SID2Universe = "0"
@@ -132,3 +232,202 @@ func TestNewHTTPServerConfiguresTimeouts(t *testing.T) {
 		t.Fatalf("unexpected IdleTimeout: %s", server.IdleTimeout)
 	}
 }
+
+func TestHandleSteamID64ToAccountIDAcceptsClanIDs(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, EndpointSID64toAID+"?steamid=103582791429521412", nil)
+	rec := httptest.NewRecorder()
+
+	HandleSteamID64ToAccountID(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	if body := rec.Body.String(); body != "4" {
+		t.Fatalf("unexpected body %q", body)
+	}
+}
+
+func TestHandleSteamID3ToSteamID64BatchAcceptsClanAndLobbyIDs(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, EndpointSID3toSID64+"?steamid=[g:1:4],[L:1:5]", nil)
+	rec := httptest.NewRecorder()
+
+	HandleSteamID3ToSteamID64(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	expected := "\"SteamIDTools\"\n{\n" +
+		"    \"[g:1:4]\" \"103582791429521412\"\n" +
+		"    \"[L:1:5]\" \"109212290963734533\"\n" +
+		"}"
+
+	if body := rec.Body.String(); body != expected {
+		t.Fatalf("unexpected body:\n%s", body)
+	}
+}
+
+func TestHandleGroupID64ToClanIDBatchUsesKeyValueOutput(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, EndpointGID64toClanID+"?steamid=103582791429521412,76561197960287930&nullterm=1", nil)
+	rec := httptest.NewRecorder()
+
+	HandleGroupID64ToClanID(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	expected := "\"SteamIDTools\"\n{\n" +
+		"    \"103582791429521412\" \"4\"\n" +
+		"    \"76561197960287930\" \"ERROR: Invalid Steam group ID (expected a group SteamID64, [g:1:N] or clan AccountID)\"\n" +
+		"}\x00"
+
+	if body := rec.Body.String(); body != expected {
+		t.Fatalf("unexpected body:\n%q", body)
+	}
+}
+
+func TestHandleSteamID64ToSteamID2UniverseOverride(t *testing.T) {
+	testCases := []struct {
+		name       string
+		query      string
+		header     string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "query parameter",
+			query:      "?steamid=76561197960287930&universe=0",
+			wantStatus: http.StatusOK,
+			wantBody:   "STEAM_0:0:11101",
+		},
+		{
+			name:       "header",
+			query:      "?steamid=76561197960287930",
+			header:     "0",
+			wantStatus: http.StatusOK,
+			wantBody:   "STEAM_0:0:11101",
+		},
+		{
+			name:       "query parameter wins over header",
+			query:      "?steamid=76561197960287930&universe=1",
+			header:     "0",
+			wantStatus: http.StatusOK,
+			wantBody:   "STEAM_1:0:11101",
+		},
+		{
+			name:       "batch honors override",
+			query:      "?steamid=76561197960287930,76561197960287931&universe=0",
+			wantStatus: http.StatusOK,
+			wantBody: "\"SteamIDTools\"\n{\n" +
+				"    \"76561197960287930\" \"STEAM_0:0:11101\"\n" +
+				"    \"76561197960287931\" \"STEAM_0:1:11101\"\n" +
+				"}",
+		},
+		{
+			name:       "invalid universe",
+			query:      "?steamid=76561197960287930&universe=7",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "Invalid SteamID2 universe (expected 0 or 1)",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, EndpointSID64toSID2+tc.query, nil)
+			if tc.header != "" {
+				req.Header.Set(SID2UniverseHeader, tc.header)
+			}
+			rec := httptest.NewRecorder()
+
+			HandleSteamID64ToSteamID2(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Fatalf("expected status %d, got %d", tc.wantStatus, rec.Code)
+			}
+			if body := rec.Body.String(); body != tc.wantBody {
+				t.Fatalf("unexpected body:\n%s", body)
+			}
+		})
+	}
+}
+
+func TestHandleConversionJSONResponses(t *testing.T) {
+	testCases := []struct {
+		name        string
+		target      string
+		accept      string
+		wantStatus  int
+		wantType    string
+		wantBody    string
+		handlerFunc http.HandlerFunc
+	}{
+		{
+			name:        "single via format query",
+			target:      EndpointSID64toAID + "?steamid=76561197960287930&format=json",
+			wantStatus:  http.StatusOK,
+			wantBody:    `{"input":"76561197960287930","value":"22202"}`,
+			handlerFunc: HandleSteamID64ToAccountID,
+		},
+		{
+			name:        "single via accept header",
+			target:      EndpointSID3toSID64 + "?steamid=[U:1:22202]",
+			accept:      "application/json",
+			wantStatus:  http.StatusOK,
+			wantBody:    `{"input":"[U:1:22202]","value":"76561197960287930"}`,
+			handlerFunc: HandleSteamID3ToSteamID64,
+		},
+		{
+			name:       "batch with localized error",
+			target:     EndpointSID64toAID + "?steamid=76561197960287930,123&format=json",
+			wantStatus: http.StatusOK,
+			wantBody: `[{"input":"76561197960287930","format":"sid64","value":"22202"},` +
+				`{"input":"123","format":"sid64","error":"invalid_length","message":"SteamID length is incorrect"}]`,
+			handlerFunc: HandleSteamID64ToAccountID,
+		},
+		{
+			name:       "single error",
+			target:     EndpointSID2toSID64 + "?steamid=STEAM_1:2:3&format=json",
+			wantStatus: http.StatusBadRequest,
+			wantType:   "application/problem+json; charset=utf-8",
+			wantBody: `{"type":"/errors/invalid_steamid2","title":"Invalid SteamID2 format (expected STEAM_X:Y:Z)","status":400,` +
+				`"detail":"Invalid SteamID2 format (expected STEAM_X:Y:Z)","error":"invalid_steamid2",` +
+				`"message":"Invalid SteamID2 format (expected STEAM_X:Y:Z)","input":"STEAM_1:2:3"}`,
+			handlerFunc: HandleSteamID2ToSteamID64,
+		},
+		{
+			name:        "health",
+			target:      EndpointHealth + "?format=json",
+			wantStatus:  http.StatusOK,
+			wantBody:    `{"status":"healthy"}`,
+			handlerFunc: HandleHealth,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+			rec := httptest.NewRecorder()
+
+			tc.handlerFunc(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Fatalf("expected status %d, got %d", tc.wantStatus, rec.Code)
+			}
+			wantType := tc.wantType
+			if wantType == "" {
+				wantType = "application/json; charset=utf-8"
+			}
+			if contentType := rec.Header().Get("Content-Type"); contentType != wantType {
+				t.Fatalf("unexpected content type %q", contentType)
+			}
+			if body := rec.Body.String(); body != tc.wantBody {
+				t.Fatalf("unexpected body %q", body)
+			}
+		})
+	}
+}
@@ -4,13 +4,16 @@ import (
 	"embed"
 	"encoding/json"
 	"fmt"
+	"sync/atomic"
 )
 
 //go:embed lang/*.json
 var langFiles embed.FS
 
 var messages map[string]string
-var backendMessages map[string]string
+
+// backendMessages is replaced whole when SIGHUP changes the backend language.
+var backendMessages atomic.Pointer[map[string]string]
 
 func loadMessages() {
 	messages = make(map[string]string)
@@ -39,7 +42,8 @@ func loadMessages() {
 }
 
 func loadBackendMessages(lang string) {
-	backendMessages = make(map[string]string)
+	loaded := make(map[string]string)
+	defer backendMessages.Store(&loaded)
 	langFilesByCode := map[string]string{
 		"en": "lang/messages_backend_en.json",
 		"es": "lang/messages_backend_es.json",
@@ -63,12 +67,16 @@ func loadBackendMessages(lang string) {
 	}
 
 	for key, value := range translated {
-		backendMessages[key] = value
+		loaded[key] = value
 	}
 }
 
+// debugEnabled reports whether per-request debug output is on: debug mode, or a
+// debug log level, which SIGHUP can set.
 func debugEnabled() bool {
-	return appCfg.Debug
+	appCfgMu.RLock()
+	defer appCfgMu.RUnlock()
+	return appCfg.Debug || appCfg.LogLevel == "debug"
 }
 
 func debugLog(format string, args ...interface{}) {
@@ -94,7 +102,7 @@ func msg(key, lang string) string {
 }
 
 func msgBackend(key string, args ...interface{}) string {
-	if value, ok := backendMessages[key]; ok {
+	if value, ok := (*backendMessages.Load())[key]; ok {
 		if len(args) > 0 {
 			return fmt.Sprintf(value, args...)
 		}
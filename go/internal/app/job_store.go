@@ -0,0 +1,191 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// jobStore keeps batch jobs until Sweep removes the expired ones. IDs reaching a
+// store are already validated by isJobID.
+type jobStore interface {
+	Save(job batchJob) error
+	Load(id string) (batchJob, bool, error)
+	Delete(id string) error
+	// List returns every stored job; it is only used at startup.
+	List() ([]batchJob, error)
+	Sweep(now time.Time) error
+}
+
+type memoryJobStore struct {
+	mu   sync.Mutex
+	jobs map[string]batchJob
+}
+
+func newMemoryJobStore() *memoryJobStore {
+	return &memoryJobStore{jobs: make(map[string]batchJob)}
+}
+
+func (s *memoryJobStore) Save(job batchJob) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[job.ID] = job
+	return nil
+}
+
+func (s *memoryJobStore) Load(id string) (batchJob, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	return job, ok, nil
+}
+
+func (s *memoryJobStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.jobs, id)
+	return nil
+}
+
+func (s *memoryJobStore) List() ([]batchJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	jobs := make([]batchJob, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+func (s *memoryJobStore) Sweep(now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, job := range s.jobs {
+		if job.expired(now) {
+			delete(s.jobs, id)
+		}
+	}
+	return nil
+}
+
+// fileJobStore writes one JSON file per job under dir, so finished results
+// survive a restart while their TTL lasts.
+type fileJobStore struct {
+	dir string
+}
+
+const jobFileExt = ".json"
+
+func newFileJobStore(dir string) (*fileJobStore, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+	return &fileJobStore{dir: dir}, nil
+}
+
+func (s *fileJobStore) path(id string) string {
+	return filepath.Join(s.dir, id+jobFileExt)
+}
+
+// Save writes through a temporary file so a concurrent Load never reads a
+// partial job.
+func (s *fileJobStore) Save(job batchJob) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(s.dir, job.ID+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(job.ID))
+}
+
+func (s *fileJobStore) Load(id string) (batchJob, bool, error) {
+	// #nosec G304 -- job IDs are validated hex strings, so the path stays inside dir.
+	data, err := os.ReadFile(s.path(id))
+	if errors.Is(err, fs.ErrNotExist) {
+		return batchJob{}, false, nil
+	}
+	if err != nil {
+		return batchJob{}, false, err
+	}
+
+	var job batchJob
+	if err := json.Unmarshal(data, &job); err != nil {
+		return batchJob{}, false, err
+	}
+	return job, true, nil
+}
+
+func (s *fileJobStore) Delete(id string) error {
+	if err := os.Remove(s.path(id)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// ids returns the IDs of the job files in dir.
+func (s *fileJobStore) ids() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if id, ok := strings.CutSuffix(entry.Name(), jobFileExt); ok && isJobID(id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// List skips files that cannot be read; Sweep leaves them alone as well.
+func (s *fileJobStore) List() ([]batchJob, error) {
+	ids, err := s.ids()
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]batchJob, 0, len(ids))
+	for _, id := range ids {
+		if job, found, err := s.Load(id); err == nil && found {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, nil
+}
+
+func (s *fileJobStore) Sweep(now time.Time) error {
+	ids, err := s.ids()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		job, found, err := s.Load(id)
+		if err != nil || !found || !job.expired(now) {
+			continue
+		}
+		if err := s.Delete(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
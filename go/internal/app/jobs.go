@@ -0,0 +1,437 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"steamid-service/pkg/keyvalues"
+)
+
+type jobStatus string
+
+const (
+	jobStatusQueued  jobStatus = "queued"
+	jobStatusRunning jobStatus = "running"
+	jobStatusDone    jobStatus = "done"
+)
+
+const (
+	// jobWorkers bounds how many jobs convert at once; the rest stay queued.
+	jobWorkers = 4
+	// maxPendingJobs bounds queued and running jobs together.
+	maxPendingJobs = 64
+	// maxFinishedJobs and maxFinishedJobBytes bound the results kept until their
+	// TTL; past either, the oldest results are dropped first.
+	maxFinishedJobs     = 256
+	maxFinishedJobBytes = 8 << 20
+	jobSweepInterval    = time.Minute
+	jobIDBytes          = 16
+)
+
+var errJobQueueFull = errors.New("job queue full")
+
+// batchJob is one asynchronous batch. A done job holds the response the
+// synchronous request would have produced, status code included.
+type batchJob struct {
+	ID          string    `json:"id"`
+	Status      jobStatus `json:"status"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	StatusCode  int       `json:"status_code,omitempty"`
+	ContentType string    `json:"content_type,omitempty"`
+	ErrorKey    string    `json:"error_key,omitempty"`
+	Body        []byte    `json:"body,omitempty"`
+}
+
+func (job batchJob) expired(now time.Time) bool {
+	return !now.Before(job.ExpiresAt)
+}
+
+func newJobID() (string, error) {
+	id := make([]byte, jobIDBytes)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
+
+// isJobID reports whether id has the shape newJobID produces.
+func isJobID(id string) bool {
+	if len(id) != 2*jobIDBytes {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if !strings.ContainsRune("0123456789abcdef", rune(id[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+// jobRunner runs submitted jobs in the background and expires them ttl after
+// they were last updated.
+type jobRunner struct {
+	store   jobStore
+	ttl     time.Duration
+	now     func() time.Time
+	slots   chan struct{}
+	pending atomic.Int32
+	running sync.WaitGroup
+
+	mu            sync.Mutex
+	finished      []finishedJob // in the order the jobs finished
+	finishedBytes int
+}
+
+// finishedJob is what the runner remembers of a stored result to enforce
+// maxFinishedJobs and maxFinishedJobBytes.
+type finishedJob struct {
+	ID        string
+	Size      int
+	ExpiresAt time.Time
+}
+
+func newJobRunner(store jobStore, ttl time.Duration) *jobRunner {
+	return &jobRunner{
+		store: store,
+		ttl:   ttl,
+		now:   time.Now,
+		slots: make(chan struct{}, jobWorkers),
+	}
+}
+
+var batchJobs = newJobRunner(newMemoryJobStore(), appCfg.JobTTL)
+
+// submit stores a queued job and serves replay through handler in the background.
+func (jr *jobRunner) submit(replay *http.Request, handler http.HandlerFunc) (batchJob, error) {
+	if jr.pending.Add(1) > maxPendingJobs {
+		jr.pending.Add(-1)
+		return batchJob{}, errJobQueueFull
+	}
+
+	id, err := newJobID()
+	if err != nil {
+		jr.pending.Add(-1)
+		return batchJob{}, err
+	}
+	job := batchJob{ID: id, Status: jobStatusQueued, ExpiresAt: jr.now().Add(jr.ttl)}
+	if err := jr.store.Save(job); err != nil {
+		jr.pending.Add(-1)
+		return batchJob{}, err
+	}
+
+	jr.running.Add(1)
+	go jr.run(job, replay, handler)
+	return job, nil
+}
+
+func (jr *jobRunner) run(job batchJob, replay *http.Request, handler http.HandlerFunc) {
+	defer jr.running.Done()
+	defer jr.pending.Add(-1)
+	jr.slots <- struct{}{}
+	defer func() { <-jr.slots }()
+
+	job.Status = jobStatusRunning
+	job.ExpiresAt = jr.now().Add(jr.ttl)
+	jr.save(job)
+
+	rec := &jobRecorder{header: make(http.Header)}
+	handler(rec, replay)
+
+	job.Status = jobStatusDone
+	job.ExpiresAt = jr.now().Add(jr.ttl)
+	job.StatusCode = rec.status
+	job.ContentType = rec.header.Get("Content-Type")
+	job.ErrorKey = rec.header.Get(ErrorHeader)
+	job.Body = rec.body.Bytes()
+	jr.save(job)
+	jr.retain(job)
+	appInfof("batch job finished: job=%s status=%d bytes=%d", job.ID, job.StatusCode, len(job.Body))
+}
+
+func (jr *jobRunner) save(job batchJob) {
+	if err := jr.store.Save(job); err != nil {
+		appErrorf("batch job not saved: job=%s status=%s error=%s", job.ID, job.Status, err.Error())
+	}
+}
+
+// wait blocks until every submitted job is done or ctx ends, and reports
+// whether the jobs finished.
+func (jr *jobRunner) wait(ctx context.Context) bool {
+	done := make(chan struct{})
+	go func() {
+		jr.running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// load returns a job that has not expired yet.
+func (jr *jobRunner) load(id string) (batchJob, bool, error) {
+	job, ok, err := jr.store.Load(id)
+	if err != nil || !ok || job.expired(jr.now()) {
+		return batchJob{}, false, err
+	}
+	return job, true, nil
+}
+
+// retain records a finished job and deletes the oldest results once more than
+// maxFinishedJobs or maxFinishedJobBytes are kept. The newest result always stays.
+func (jr *jobRunner) retain(job batchJob) {
+	jr.mu.Lock()
+	jr.finished = append(jr.finished, finishedJob{ID: job.ID, Size: len(job.Body), ExpiresAt: job.ExpiresAt})
+	jr.finishedBytes += len(job.Body)
+	var evicted []string
+	for len(jr.finished) > 1 && (len(jr.finished) > maxFinishedJobs || jr.finishedBytes > maxFinishedJobBytes) {
+		oldest := jr.finished[0]
+		jr.finished = jr.finished[1:]
+		jr.finishedBytes -= oldest.Size
+		evicted = append(evicted, oldest.ID)
+	}
+	jr.mu.Unlock()
+
+	for _, id := range evicted {
+		if err := jr.store.Delete(id); err != nil {
+			appWarnf("batch job not evicted: job=%s error=%s", id, err.Error())
+		}
+	}
+	if len(evicted) > 0 {
+		appInfof("batch job results evicted: count=%d", len(evicted))
+	}
+}
+
+// sweep removes expired jobs from the store.
+func (jr *jobRunner) sweep() {
+	now := jr.now()
+	jr.mu.Lock()
+	kept := jr.finished[:0]
+	for _, finished := range jr.finished {
+		if now.Before(finished.ExpiresAt) {
+			kept = append(kept, finished)
+			continue
+		}
+		jr.finishedBytes -= finished.Size
+	}
+	jr.finished = kept
+	jr.mu.Unlock()
+
+	if err := jr.store.Sweep(now); err != nil {
+		appWarnf("batch job sweep failed: error=%s", err.Error())
+	}
+}
+
+// sweepEvery sweeps expired jobs every interval until the returned stop is called.
+func (jr *jobRunner) sweepEvery(interval time.Duration) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				jr.sweep()
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+// recoverJobs runs at startup. Jobs a previous process left queued or running
+// are never resumed, so they finish with job_interrupted instead of staying
+// pending until they expire; finished results count towards the caps.
+func (jr *jobRunner) recoverJobs() error {
+	jobs, err := jr.store.List()
+	if err != nil {
+		return err
+	}
+
+	now := jr.now()
+	interrupted := 0
+	for i, job := range jobs {
+		if job.Status == jobStatusDone || job.expired(now) {
+			continue
+		}
+		job.Status = jobStatusDone
+		job.ExpiresAt = now.Add(jr.ttl)
+		job.StatusCode, _ = errorStatus(ErrorJobInterrupted)
+		job.ErrorKey = ErrorJobInterrupted.Key()
+		job.ContentType = ""
+		job.Body = nil
+		if err := jr.store.Save(job); err != nil {
+			return err
+		}
+		jobs[i] = job
+		interrupted++
+	}
+
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].ExpiresAt.Before(jobs[j].ExpiresAt) })
+	for _, job := range jobs {
+		if !job.expired(now) {
+			jr.retain(job)
+		}
+	}
+	if interrupted > 0 {
+		appWarnf("batch jobs interrupted by restart: count=%d", interrupted)
+	}
+	return nil
+}
+
+// jobRecorder captures the response of a replayed request.
+type jobRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (rec *jobRecorder) Header() http.Header {
+	return rec.header
+}
+
+func (rec *jobRecorder) WriteHeader(status int) {
+	if rec.status == 0 {
+		rec.status = status
+	}
+}
+
+func (rec *jobRecorder) Write(b []byte) (int, error) {
+	rec.WriteHeader(http.StatusOK)
+	return rec.body.Write(b)
+}
+
+// wantsAsync reports whether async=1 or Prefer: respond-async asked to run a
+// batch as a job.
+func wantsAsync(r *http.Request) bool {
+	return r.URL.Query().Get("async") == "1" || strings.Contains(r.Header.Get("Prefer"), "respond-async")
+}
+
+// newJobReplay copies r without the async request so the job can serve it after
+// the client has gone. A POST body is read now, under the usual size cap.
+func newJobReplay(w http.ResponseWriter, r *http.Request, lang string) (*http.Request, SteamIDError, string) {
+	replay := r.Clone(context.WithoutCancel(r.Context()))
+	query := replay.URL.Query()
+	query.Del("async")
+	replay.URL.RawQuery = query.Encode()
+	replay.Header.Del("Prefer")
+
+	if r.Method == http.MethodPost {
+		_, _, maxBodyBytes := batchLimits()
+		data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				return nil, ErrorBodyTooLarge, ""
+			}
+			return nil, ErrorInvalidFormat, msg("batch_parse_failed", lang)
+		}
+		replay.Body = io.NopCloser(bytes.NewReader(data))
+		replay.ContentLength = int64(len(data))
+	}
+
+	return replay, ErrorNone, ""
+}
+
+// submitBatchJob answers with a queued job whose result is the response handler
+// gives to the same request without async.
+func submitBatchJob(w http.ResponseWriter, r *http.Request, lang string, handler http.HandlerFunc) {
+	replay, bodyErr, message := newJobReplay(w, r, lang)
+	if !bodyErr.IsValid() {
+		writeErrorResponse(w, r, bodyErr, message, "job body rejected")
+		return
+	}
+
+	job, err := batchJobs.submit(replay, handler)
+	switch {
+	case errors.Is(err, errJobQueueFull):
+		writeErrorResponse(w, r, ErrorServiceUnavailable, msgf("job_queue_full", lang, maxPendingJobs), "job queue full")
+		return
+	case err != nil:
+		writeErrorResponse(w, r, ErrorServiceUnavailable, "", "job not saved: "+err.Error())
+		return
+	}
+
+	appInfof("batch job submitted: job=%s path=%s remote_addr=%s", job.ID, r.URL.Path, r.RemoteAddr)
+	w.Header().Set("Location", EndpointJobs+"/"+job.ID)
+	writeJobStatus(w, r, job)
+}
+
+// writeJobStatus answers with the job ID and status. It is a 200 so SourceMod
+// transports, which only accept 200, hand the body to SteamIDTools_OnRequestFinished.
+func writeJobStatus(w http.ResponseWriter, r *http.Request, job batchJob) {
+	w.Header().Set(JobStatusHeader, string(job.Status))
+	document := keyvalues.NewSection("SteamIDTools",
+		keyvalues.NewValue("job", job.ID),
+		keyvalues.NewValue("status", string(job.Status)),
+	)
+
+	switch {
+	case wantsKeyValueBinary(r):
+		writeKeyValueBinaryResponse(w, r, http.StatusOK, document)
+	case wantsJSON(r):
+		writeJSONResponse(w, r, http.StatusOK, JobResponse{Job: job.ID, Status: job.Status})
+	default:
+		writeKeyValueResponse(w, string(keyvalues.Marshal(document)), hasNullTerm(r))
+	}
+}
+
+// handleJob serves /jobs/{id}, and /jobs?steamid={id} so the SourceMod
+// SteamIDTools_RequestConversion native can poll a job like any conversion.
+func handleJob(w http.ResponseWriter, r *http.Request) {
+	lang := getLang(r)
+	id := r.URL.Query().Get("steamid")
+	if r.URL.Path != EndpointJobs {
+		id = strings.TrimPrefix(r.URL.Path, EndpointJobs+"/")
+	}
+	if id == "" {
+		writeErrorResponse(w, r, ErrorMissingParameter, msg("job_param_required", lang), "job id missing")
+		return
+	}
+
+	var job batchJob
+	found := false
+	if isJobID(id) {
+		var err error
+		job, found, err = batchJobs.load(id)
+		if err != nil {
+			writeErrorResponse(w, r, ErrorServiceUnavailable, "", "job not loaded: "+err.Error())
+			return
+		}
+	}
+	if !found {
+		writeErrorResponse(w, r, ErrorJobNotFound, "", id)
+		return
+	}
+
+	if job.Status != jobStatusDone {
+		writeJobStatus(w, r, job)
+		return
+	}
+
+	w.Header().Set(JobStatusHeader, string(job.Status))
+	if job.Body == nil && job.ErrorKey != "" {
+		// Jobs failed outside a request, such as job_interrupted, have no stored
+		// response and answer in the representation of the poll.
+		writeErrorResponse(w, r, SteamIDError(job.ErrorKey), "", "job "+job.ID)
+		return
+	}
+	if job.ErrorKey != "" {
+		w.Header().Set(ErrorHeader, job.ErrorKey)
+	}
+	writeEncodedResponse(w, r, job.StatusCode, job.ContentType, job.Body, nil)
+}
@@ -0,0 +1,361 @@
+package app
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// pollJob polls location until the job is done and returns the final response.
+func pollJob(t *testing.T, handler http.Handler, location string, header http.Header) *httptest.ResponseRecorder {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		req := httptest.NewRequest(http.MethodGet, location, nil)
+		for key, values := range header {
+			req.Header[key] = values
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Header().Get(JobStatusHeader) == string(jobStatusDone) {
+			return rec
+		}
+		if rec.Code != http.StatusOK {
+			t.Fatalf("poll failed with %d %q", rec.Code, rec.Body.String())
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job %s did not finish, last status %q", location, rec.Header().Get(JobStatusHeader))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestBatchJobReturnsSynchronousResponse(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		method      string
+		target      string
+		body        string
+		contentType string
+		accept      string
+		wantStatus  int
+	}{
+		{
+			name:       "get batch",
+			method:     http.MethodGet,
+			target:     EndpointSID64toAID + "?steamid=76561197960287930,76561197960287931",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:        "post json batch",
+			method:      http.MethodPost,
+			target:      EndpointConvert + "?from=auto&to=sid64",
+			body:        `["STEAM_1:0:11101","22203"]`,
+			contentType: "application/json",
+			accept:      "application/json",
+			wantStatus:  http.StatusOK,
+		},
+		{
+			name:       "batch error is kept",
+			method:     http.MethodGet,
+			target:     EndpointSID64toAID + "?steamid=76561197960287930,76561197960287930",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	mux := newHandlerMux(false)
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			newRequest := func(target string) *http.Request {
+				req := httptest.NewRequest(tc.method, target, strings.NewReader(tc.body))
+				if tc.contentType != "" {
+					req.Header.Set("Content-Type", tc.contentType)
+				}
+				if tc.accept != "" {
+					req.Header.Set("Accept", tc.accept)
+				}
+				return req
+			}
+
+			want := httptest.NewRecorder()
+			mux.ServeHTTP(want, newRequest(tc.target))
+			if want.Code != tc.wantStatus {
+				t.Fatalf("expected synchronous status %d, got %d", tc.wantStatus, want.Code)
+			}
+
+			submitted := httptest.NewRecorder()
+			mux.ServeHTTP(submitted, newRequest(tc.target+"&async=1"))
+			location := submitted.Header().Get("Location")
+			if submitted.Code != http.StatusOK || !strings.HasPrefix(location, EndpointJobs+"/") {
+				t.Fatalf("unexpected submission %d %q location=%q", submitted.Code, submitted.Body.String(), location)
+			}
+			id := strings.TrimPrefix(location, EndpointJobs+"/")
+			if !strings.Contains(submitted.Body.String(), id) {
+				t.Fatalf("submission body %q does not name job %s", submitted.Body.String(), id)
+			}
+
+			got := pollJob(t, mux, location, http.Header{})
+			if got.Code != want.Code || got.Body.String() != want.Body.String() {
+				t.Fatalf("expected %d %q, got %d %q", want.Code, want.Body.String(), got.Code, got.Body.String())
+			}
+			for _, header := range []string{"Content-Type", ErrorHeader} {
+				if gotValue, wantValue := got.Header().Get(header), want.Header().Get(header); gotValue != wantValue {
+					t.Fatalf("expected %s %q, got %q", header, wantValue, gotValue)
+				}
+			}
+		})
+	}
+}
+
+func TestBatchJobStatusDocument(t *testing.T) {
+	t.Parallel()
+
+	job := batchJob{ID: strings.Repeat("ab", jobIDBytes), Status: jobStatusQueued}
+
+	testCases := []struct {
+		name   string
+		target string
+		want   string
+	}{
+		{
+			name:   "keyvalue",
+			target: EndpointJobs,
+			want:   "\"SteamIDTools\"\n{\n    \"job\" \"" + job.ID + "\"\n    \"status\" \"queued\"\n}",
+		},
+		{
+			name:   "json",
+			target: EndpointJobs + "?format=json",
+			want:   `{"job":"` + job.ID + `","status":"queued"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+			writeJobStatus(rec, httptest.NewRequest(http.MethodGet, tc.target, nil), job)
+
+			if rec.Code != http.StatusOK || rec.Body.String() != tc.want {
+				t.Fatalf("unexpected response %d %q", rec.Code, rec.Body.String())
+			}
+			if got := rec.Header().Get(JobStatusHeader); got != "queued" {
+				t.Fatalf("unexpected status header %q", got)
+			}
+		})
+	}
+}
+
+func TestHandleJobRejectsUnknownIDs(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		target     string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "missing id",
+			target:     EndpointJobs,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "job id required (/jobs/{id} or steamid parameter)",
+		},
+		{
+			name:       "unknown id",
+			target:     EndpointJobs + "/" + strings.Repeat("0", 2*jobIDBytes),
+			wantStatus: http.StatusNotFound,
+			wantBody:   "Job not found or expired",
+		},
+		{
+			name:       "malformed id by query",
+			target:     EndpointJobs + "?steamid=..%2Fsecret",
+			wantStatus: http.StatusNotFound,
+			wantBody:   "Job not found or expired",
+		},
+	}
+
+	mux := newHandlerMux(false)
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.target, nil))
+
+			if rec.Code != tc.wantStatus || rec.Body.String() != tc.wantBody {
+				t.Fatalf("unexpected response %d %q", rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestJobStoresExpireJobs(t *testing.T) {
+	t.Parallel()
+
+	fileStore, err := newFileJobStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("newFileJobStore: %v", err)
+	}
+
+	testCases := []struct {
+		name  string
+		store jobStore
+	}{
+		{name: "memory", store: newMemoryJobStore()},
+		{name: "file", store: fileStore},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+			runner := newJobRunner(tc.store, time.Minute)
+			runner.now = func() time.Time { return now }
+
+			job := batchJob{
+				ID:          strings.Repeat("cd", jobIDBytes),
+				Status:      jobStatusDone,
+				ExpiresAt:   now.Add(time.Minute),
+				StatusCode:  http.StatusOK,
+				ContentType: contentTypePlainText,
+				Body:        []byte("\"SteamIDTools\"\n{\n}"),
+			}
+			if err := tc.store.Save(job); err != nil {
+				t.Fatalf("Save: %v", err)
+			}
+
+			got, ok, err := runner.load(job.ID)
+			if err != nil || !ok || string(got.Body) != string(job.Body) || got.StatusCode != job.StatusCode {
+				t.Fatalf("unexpected load %+v ok=%v err=%v", got, ok, err)
+			}
+
+			now = now.Add(time.Minute)
+			if _, ok, err := runner.load(job.ID); ok || err != nil {
+				t.Fatalf("expected expired job to be hidden, ok=%v err=%v", ok, err)
+			}
+
+			if err := tc.store.Sweep(now); err != nil {
+				t.Fatalf("Sweep: %v", err)
+			}
+			if _, ok, err := tc.store.Load(job.ID); ok || err != nil {
+				t.Fatalf("expected sweep to remove the job, ok=%v err=%v", ok, err)
+			}
+		})
+	}
+}
+
+func TestJobRunnerCapsFinishedResults(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		jobs        int
+		bodySize    int
+		wantEvicted int
+	}{
+		{name: "count", jobs: maxFinishedJobs + 2, bodySize: 16, wantEvicted: 2},
+		{name: "bytes", jobs: 3, bodySize: maxFinishedJobBytes / 2, wantEvicted: 1},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := newMemoryJobStore()
+			runner := newJobRunner(store, time.Minute)
+			body := make([]byte, tc.bodySize)
+			ids := make([]string, tc.jobs)
+			for i := range ids {
+				ids[i] = fmt.Sprintf("%0*x", 2*jobIDBytes, i)
+				job := batchJob{ID: ids[i], Status: jobStatusDone, ExpiresAt: runner.now().Add(time.Minute), Body: body}
+				if err := store.Save(job); err != nil {
+					t.Fatalf("Save: %v", err)
+				}
+				runner.retain(job)
+			}
+
+			for i, id := range ids {
+				_, ok, err := store.Load(id)
+				if err != nil || ok == (i < tc.wantEvicted) {
+					t.Fatalf("job %d: expected evicted=%v, found=%v err=%v", i, i < tc.wantEvicted, ok, err)
+				}
+			}
+		})
+	}
+}
+
+func TestJobRunnerSweepsOnATicker(t *testing.T) {
+	t.Parallel()
+
+	store := newMemoryJobStore()
+	runner := newJobRunner(store, time.Minute)
+	id := strings.Repeat("ef", jobIDBytes)
+	if err := store.Save(batchJob{ID: id, Status: jobStatusDone, ExpiresAt: runner.now()}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	stop := runner.sweepEvery(time.Millisecond)
+	defer stop()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, ok, _ := store.Load(id); !ok {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expired job was not swept without new submissions")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestRecoverJobsFailsInterruptedJobs(t *testing.T) {
+	previous := batchJobs
+	t.Cleanup(func() { batchJobs = previous })
+
+	store, err := newFileJobStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("newFileJobStore: %v", err)
+	}
+	now := time.Now()
+	running := batchJob{ID: strings.Repeat("ab", jobIDBytes), Status: jobStatusRunning, ExpiresAt: now.Add(time.Minute)}
+	done := batchJob{ID: strings.Repeat("cd", jobIDBytes), Status: jobStatusDone, ExpiresAt: now.Add(time.Minute), StatusCode: http.StatusOK, Body: []byte("ok")}
+	for _, job := range []batchJob{running, done} {
+		if err := store.Save(job); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+
+	batchJobs = newJobRunner(store, time.Minute)
+	if err := batchJobs.recoverJobs(); err != nil {
+		t.Fatalf("recoverJobs: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	HandleJob(rec, httptest.NewRequest(http.MethodGet, EndpointJobs+"/"+running.ID+"?format=json", nil))
+	if rec.Code != http.StatusServiceUnavailable || rec.Header().Get(ErrorHeader) != string(ErrorJobInterrupted) ||
+		rec.Header().Get(JobStatusHeader) != string(jobStatusDone) || !strings.Contains(rec.Body.String(), `"error":"job_interrupted"`) {
+		t.Fatalf("unexpected interrupted job response %d %v %s", rec.Code, rec.Header(), rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	HandleJob(rec, httptest.NewRequest(http.MethodGet, EndpointJobs+"/"+done.ID, nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "ok" {
+		t.Fatalf("finished job changed by recovery: %d %q", rec.Code, rec.Body.String())
+	}
+}
@@ -30,14 +30,14 @@ func (r *responseRecorder) Write(p []byte) (int, error) {
 	return n, err
 }
 
-func configureLogger(debugMode bool) {
-	zerolog.TimeFieldFormat = time.RFC3339
-
-	level := zerolog.InfoLevel
-	if debugMode {
-		level = zerolog.DebugLevel
-	}
+// Unwrap lets http.ResponseController reach the underlying writer to flush and
+// adjust deadlines on streaming responses.
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
 
+func configureLogger(debugMode bool, level zerolog.Level) {
+	zerolog.TimeFieldFormat = time.RFC3339
 	zerolog.SetGlobalLevel(level)
 
 	logger := zerolog.New(os.Stdout).
@@ -79,6 +79,9 @@ func accessLogMiddleware(next http.Handler) http.Handler {
 			event = zlog.Warn()
 		}
 
+		if traceID := spanFromContext(r.Context()).traceID(); traceID != "" {
+			event = event.Str("trace_id", traceID)
+		}
 		event.
 			Str("method", r.Method).
 			Str("path", r.URL.Path).
@@ -112,6 +115,10 @@ func appWarnf(format string, args ...interface{}) {
 	zlog.Warn().Msgf(format, args...)
 }
 
+func appWarnEvent() *zerolog.Event {
+	return zlog.Warn()
+}
+
 func appErrorf(format string, args ...interface{}) {
 	zlog.Error().Msgf(format, args...)
 }
@@ -130,7 +130,7 @@ func TestLogStartupEmitsStructuredEvents(t *testing.T) {
 		}
 
 		if entry["message"] == "endpoints registered" {
-			if got := entry["endpoint_count"]; got != float64(8) {
+			if got := entry["endpoint_count"]; got != float64(45) {
 				t.Fatalf("unexpected endpoint_count %v", got)
 			}
 
@@ -139,7 +139,7 @@ func TestLogStartupEmitsStructuredEvents(t *testing.T) {
 				t.Fatalf("expected endpoints array, got %T", entry["endpoints"])
 			}
 
-			if len(endpoints) != 8 {
+			if len(endpoints) != 45 {
 				t.Fatalf("unexpected endpoints length %d", len(endpoints))
 			}
 
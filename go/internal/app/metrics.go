@@ -0,0 +1,317 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The service writes the Prometheus text exposition format itself; go.mod keeps
+// external dependencies to logging and Swagger.
+
+var (
+	requestDurationBuckets = []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+	batchSizeBuckets       = []float64{1, 2, 5, 10, 32, 100, 500, 1000, 5000, 10000}
+)
+
+const contentTypeMetrics = "text/plain; version=0.0.4; charset=utf-8"
+
+// Error scopes separate failed requests from failed entries of a successful batch.
+const (
+	errorScopeRequest = "request"
+	errorScopeItem    = "item"
+)
+
+type histogram struct {
+	buckets []float64
+	counts  []uint64
+	sum     float64
+	count   uint64
+}
+
+func newHistogram(buckets []float64) *histogram {
+	return &histogram{buckets: buckets, counts: make([]uint64, len(buckets))}
+}
+
+func (h *histogram) observe(value float64) {
+	for i, bound := range h.buckets {
+		if value <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += value
+	h.count++
+}
+
+type requestKey struct {
+	Endpoint string
+	Method   string
+	Code     int
+}
+
+type errorKey struct {
+	Code  SteamIDError
+	Scope string
+}
+
+// metricsRegistry holds every metric the service exposes. Labels are bounded:
+// endpoints go through metricsEndpointLabel, methods through metricsMethodLabel
+// and errors are SteamIDError keys.
+type metricsRegistry struct {
+	mu           sync.Mutex
+	requests     map[requestKey]uint64
+	durations    map[string]*histogram
+	batchSizes   map[string]*histogram
+	errors       map[errorKey]uint64
+	healthChecks map[string]uint64
+}
+
+func newMetricsRegistry() *metricsRegistry {
+	return &metricsRegistry{
+		requests:     make(map[requestKey]uint64),
+		durations:    make(map[string]*histogram),
+		batchSizes:   make(map[string]*histogram),
+		errors:       make(map[errorKey]uint64),
+		healthChecks: make(map[string]uint64),
+	}
+}
+
+var metrics = newMetricsRegistry()
+
+func (m *metricsRegistry) observeRequest(endpoint, method string, code int, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests[requestKey{Endpoint: endpoint, Method: method, Code: code}]++
+	h, ok := m.durations[endpoint]
+	if !ok {
+		h = newHistogram(requestDurationBuckets)
+		m.durations[endpoint] = h
+	}
+	h.observe(duration.Seconds())
+}
+
+func (m *metricsRegistry) observeBatchSize(endpoint string, size int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	h, ok := m.batchSizes[endpoint]
+	if !ok {
+		h = newHistogram(batchSizeBuckets)
+		m.batchSizes[endpoint] = h
+	}
+	h.observe(float64(size))
+}
+
+func (m *metricsRegistry) countError(err SteamIDError, scope string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.errors[errorKey{Code: err, Scope: scope}]++
+}
+
+func (m *metricsRegistry) countHealthCheck(outcome string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.healthChecks[outcome]++
+}
+
+// metricsEndpointLabels is the fixed label set: every conversion route and
+// service endpoint, built once because every request looks its path up here.
+var metricsEndpointLabels = sync.OnceValue(func() map[string]bool {
+	labels := make(map[string]bool, len(conversionRoutes())+len(serviceEndpoints)+1)
+	for _, route := range conversionRoutes() {
+		labels[route.Path] = true
+	}
+	for _, endpoint := range serviceEndpoints {
+		labels[endpoint] = true
+	}
+	labels[EndpointMetrics] = true
+	return labels
+})
+
+// metricsEndpointPrefixes group endpoints with path parameters under one label.
+var metricsEndpointPrefixes = []string{EndpointJobs, EndpointErrors, "/swagger"}
+
+// metricsEndpointLabel maps a request path to a registered endpoint, so unknown
+// paths and path parameters cannot grow the label set.
+func metricsEndpointLabel(path string) string {
+	if metricsEndpointLabels()[path] {
+		return path
+	}
+	for _, prefix := range metricsEndpointPrefixes {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return prefix
+		}
+	}
+	return "other"
+}
+
+// metricsMethodLabel keeps the methods the service serves and reports any other
+// token as "other", so clients cannot add series by inventing methods.
+func metricsMethodLabel(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodPost, http.MethodHead, http.MethodOptions:
+		return method
+	}
+	return "other"
+}
+
+func metricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		startedAt := time.Now()
+		recorder := &responseRecorder{
+			ResponseWriter: w,
+			status:         http.StatusOK,
+		}
+
+		next.ServeHTTP(recorder, r)
+
+		metrics.observeRequest(metricsEndpointLabel(r.URL.Path), metricsMethodLabel(r.Method), recorder.status, time.Since(startedAt))
+	})
+}
+
+// metricsSeparate reports whether /metrics is served on its own listener.
+func metricsSeparate() bool {
+	return appCfg.MetricsAddr != ""
+}
+
+// metricsURL is where /metrics is reachable for the startup log.
+func metricsURL(baseURL string) string {
+	if !metricsSeparate() {
+		return baseURL + EndpointMetrics
+	}
+	host, port, err := net.SplitHostPort(appCfg.MetricsAddr)
+	if err != nil {
+		return appCfg.MetricsAddr + EndpointMetrics
+	}
+	return fmt.Sprintf("http://%s%s", net.JoinHostPort(publicHost(host), port), EndpointMetrics)
+}
+
+func newMetricsMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle(EndpointMetrics, http.HandlerFunc(HandleMetrics))
+	return mux
+}
+
+func handleMetrics(w http.ResponseWriter, r *http.Request) {
+	var b strings.Builder
+	metrics.write(&b)
+	body := b.String()
+
+	w.Header().Set("Content-Type", contentTypeMetrics)
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(http.StatusOK)
+	writePlainTextBody(w, body)
+}
+
+// write renders every metric in the Prometheus text format, with series sorted
+// so the output is stable.
+func (m *metricsRegistry) write(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	writeMetricHeader(w, "steamidtools_build_info", "gauge", "Build information of the running service.")
+	writeSample(w, "steamidtools_build_info", labels("version", Version, "goversion", runtime.Version()), 1)
+
+	writeMetricHeader(w, "steamidtools_http_requests_total", "counter", "HTTP requests by endpoint, method and status code.")
+	requestKeys := make([]requestKey, 0, len(m.requests))
+	for key := range m.requests {
+		requestKeys = append(requestKeys, key)
+	}
+	sort.Slice(requestKeys, func(i, j int) bool {
+		a, b := requestKeys[i], requestKeys[j]
+		if a.Endpoint != b.Endpoint {
+			return a.Endpoint < b.Endpoint
+		}
+		if a.Method != b.Method {
+			return a.Method < b.Method
+		}
+		return a.Code < b.Code
+	})
+	for _, key := range requestKeys {
+		writeSample(w, "steamidtools_http_requests_total",
+			labels("endpoint", key.Endpoint, "method", key.Method, "code", strconv.Itoa(key.Code)), float64(m.requests[key]))
+	}
+
+	writeMetricHeader(w, "steamidtools_http_request_duration_seconds", "histogram", "HTTP request latency by endpoint.")
+	writeHistograms(w, "steamidtools_http_request_duration_seconds", m.durations)
+
+	writeMetricHeader(w, "steamidtools_batch_size", "histogram", "Entries per batch request by endpoint.")
+	writeHistograms(w, "steamidtools_batch_size", m.batchSizes)
+
+	writeMetricHeader(w, "steamidtools_errors_total", "counter", "Errors by SteamIDError code; scope is request for failed requests and item for failed batch entries.")
+	errorKeys := make([]errorKey, 0, len(m.errors))
+	for key := range m.errors {
+		errorKeys = append(errorKeys, key)
+	}
+	sort.Slice(errorKeys, func(i, j int) bool {
+		a, b := errorKeys[i], errorKeys[j]
+		if a.Code != b.Code {
+			return a.Code < b.Code
+		}
+		return a.Scope < b.Scope
+	})
+	for _, key := range errorKeys {
+		writeSample(w, "steamidtools_errors_total", labels("code", key.Code.Key(), "scope", key.Scope), float64(m.errors[key]))
+	}
+
+	writeMetricHeader(w, "steamidtools_health_checks_total", "counter", "Health check outcomes.")
+	for _, outcome := range []string{"healthy", "unhealthy"} {
+		writeSample(w, "steamidtools_health_checks_total", labels("outcome", outcome), float64(m.healthChecks[outcome]))
+	}
+}
+
+func writeHistograms(w io.Writer, name string, histograms map[string]*histogram) {
+	endpoints := make([]string, 0, len(histograms))
+	for endpoint := range histograms {
+		endpoints = append(endpoints, endpoint)
+	}
+	sort.Strings(endpoints)
+
+	for _, endpoint := range endpoints {
+		h := histograms[endpoint]
+		for i, bound := range h.buckets {
+			writeSample(w, name+"_bucket", labels("endpoint", endpoint, "le", formatMetricValue(bound)), float64(h.counts[i]))
+		}
+		writeSample(w, name+"_bucket", labels("endpoint", endpoint, "le", "+Inf"), float64(h.count))
+		writeSample(w, name+"_sum", labels("endpoint", endpoint), h.sum)
+		writeSample(w, name+"_count", labels("endpoint", endpoint), float64(h.count))
+	}
+}
+
+func writeMetricHeader(w io.Writer, name, metricType, help string) {
+	_, _ = fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+}
+
+func writeSample(w io.Writer, name, labelSet string, value float64) {
+	_, _ = fmt.Fprintf(w, "%s%s %s\n", name, labelSet, formatMetricValue(value))
+}
+
+// labels renders name/value pairs as a label set, escaping values as the text
+// format requires.
+func labels(pairs ...string) string {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(pairs[i])
+		b.WriteString(`="`)
+		b.WriteString(metricLabelEscaper.Replace(pairs[i+1]))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+var metricLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatMetricValue(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
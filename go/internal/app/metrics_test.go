@@ -0,0 +1,140 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetricsEndpointLabel(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		path string
+		want string
+	}{
+		{path: EndpointSID64toAID, want: EndpointSID64toAID},
+		{path: EndpointConvert, want: EndpointConvert},
+		{path: EndpointJobs + "/" + strings.Repeat("ab", jobIDBytes), want: EndpointJobs},
+		{path: EndpointErrors + "/invalid_steamid2", want: EndpointErrors},
+		{path: "/swagger/index.html", want: "/swagger"},
+		{path: EndpointMetrics, want: EndpointMetrics},
+		{path: "/wp-login.php", want: "other"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.path, func(t *testing.T) {
+			t.Parallel()
+
+			if got := metricsEndpointLabel(tc.path); got != tc.want {
+				t.Fatalf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestMetricsMethodLabel(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		method string
+		want   string
+	}{
+		{method: http.MethodGet, want: http.MethodGet},
+		{method: http.MethodPost, want: http.MethodPost},
+		{method: http.MethodHead, want: http.MethodHead},
+		{method: http.MethodOptions, want: http.MethodOptions},
+		{method: http.MethodDelete, want: "other"},
+		{method: "get", want: "other"},
+		{method: "X-RANDOM-1234", want: "other"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.method, func(t *testing.T) {
+			t.Parallel()
+
+			if got := metricsMethodLabel(tc.method); got != tc.want {
+				t.Fatalf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestMetricsEndpointLabelDoesNotAllocate(t *testing.T) {
+	for _, path := range []string{EndpointSID64toAID, EndpointJobs + "/" + strings.Repeat("ab", jobIDBytes), "/wp-login.php"} {
+		if allocs := testing.AllocsPerRun(100, func() { metricsEndpointLabel(path) }); allocs != 0 {
+			t.Fatalf("metricsEndpointLabel(%q) allocated %.0f times per call", path, allocs)
+		}
+	}
+}
+
+func TestMetricsRegistryWrite(t *testing.T) {
+	t.Parallel()
+
+	registry := newMetricsRegistry()
+	registry.observeRequest(EndpointSID64toAID, http.MethodGet, http.StatusOK, 3*time.Millisecond)
+	registry.observeBatchSize(EndpointSID64toAID, 3)
+	registry.countError(ErrorInvalidSteamID64, errorScopeItem)
+	registry.countHealthCheck("healthy")
+
+	var b strings.Builder
+	registry.write(&b)
+	got := b.String()
+
+	for _, want := range []string{
+		`steamidtools_build_info{version="` + Version + `",goversion="` + runtime.Version() + `"} 1`,
+		`steamidtools_http_requests_total{endpoint="/SID64toAID",method="GET",code="200"} 1`,
+		`steamidtools_http_request_duration_seconds_bucket{endpoint="/SID64toAID",le="0.0025"} 0`,
+		`steamidtools_http_request_duration_seconds_bucket{endpoint="/SID64toAID",le="0.005"} 1`,
+		`steamidtools_http_request_duration_seconds_bucket{endpoint="/SID64toAID",le="+Inf"} 1`,
+		`steamidtools_http_request_duration_seconds_count{endpoint="/SID64toAID"} 1`,
+		`steamidtools_batch_size_bucket{endpoint="/SID64toAID",le="2"} 0`,
+		`steamidtools_batch_size_bucket{endpoint="/SID64toAID",le="5"} 1`,
+		`steamidtools_batch_size_sum{endpoint="/SID64toAID"} 3`,
+		`steamidtools_errors_total{code="invalid_steamid64",scope="item"} 1`,
+		`steamidtools_health_checks_total{outcome="healthy"} 1`,
+		`steamidtools_health_checks_total{outcome="unhealthy"} 0`,
+		"# TYPE steamidtools_batch_size histogram",
+	} {
+		if !strings.Contains(got, want+"\n") {
+			t.Fatalf("missing %q in\n%s", want, got)
+		}
+	}
+}
+
+func TestHandleMetricsCountsRequests(t *testing.T) {
+	t.Parallel()
+
+	handler := metricsMiddleware(newHandlerMux(false))
+	for _, target := range []string{
+		EndpointSID64toAID + "?steamid=76561197960287930,nope",
+		EndpointSID2toSID64 + "?steamid=STEAM_1:2:3",
+	} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("BREW", EndpointHealth, nil))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, EndpointMetrics, nil))
+
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != contentTypeMetrics {
+		t.Fatalf("unexpected response %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	body := rec.Body.String()
+	for _, want := range []string{
+		`steamidtools_http_requests_total{endpoint="/SID2toSID64",method="GET",code="400"}`,
+		`steamidtools_http_requests_total{endpoint="/health",method="other",`,
+		`steamidtools_batch_size_count{endpoint="/SID64toAID"}`,
+		`steamidtools_errors_total{code="invalid_steamid2",scope="request"}`,
+		`steamidtools_errors_total{code="invalid_length",scope="item"}`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("missing %q in\n%s", want, body)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package app
+
+import (
+	"strings"
+	"sync"
+)
+
+type steamIDFormat string
+
+const (
+	steamIDFormatUnknown steamIDFormat = ""
+	steamIDFormatSID64   steamIDFormat = "sid64"
+	steamIDFormatSID2    steamIDFormat = "sid2"
+	steamIDFormatSID3    steamIDFormat = "sid3"
+	steamIDFormatAID     steamIDFormat = "aid"
+	steamIDFormatHex     steamIDFormat = "hex"
+	steamIDFormatURL     steamIDFormat = "url"
+	steamIDFormatGID64   steamIDFormat = "gid64"
+	steamIDFormatClanID  steamIDFormat = "clanid"
+	steamIDFormatGID3    steamIDFormat = "gid3"
+
+	// steamIDFormatAuto detects the source format of every input on its own.
+	steamIDFormatAuto steamIDFormat = "auto"
+)
+
+type conversionFamily string
+
+const (
+	conversionFamilyAccount conversionFamily = "account"
+	conversionFamilyGroup   conversionFamily = "group"
+)
+
+// steamIDFormatSpec registers one input/output format. Every conversion is derived
+// as toSteamID64 followed by fromSteamID64, so adding a format here adds a route
+// to and from every other format of the same family.
+type steamIDFormatSpec struct {
+	Format        steamIDFormat
+	Token         string
+	Family        conversionFamily
+	Example       string
+	toSteamID64   conversionStep
+	fromSteamID64 conversionStep
+}
+
+type conversionRoute struct {
+	Path   string
+	From   steamIDFormatSpec
+	To     steamIDFormatSpec
+	Config conversionHandlerConfig
+}
+
+var steamID64ErrorContext = func(lang, value string) string {
+	return msgf("steamid64", lang, value)
+}
+
+var steamIDFormatRegistry = labelConversionSteps([]steamIDFormatSpec{
+	{
+		Format:        steamIDFormatSID64,
+		Token:         "SID64",
+		Family:        conversionFamilyAccount,
+		Example:       "76561197960287930",
+		toSteamID64:   conversionStep{convert: SID64FromSID64},
+		fromSteamID64: conversionStep{convert: SID64FromSID64},
+	},
+	{
+		Format:        steamIDFormatAID,
+		Token:         "AID",
+		Family:        conversionFamilyAccount,
+		Example:       "22202",
+		toSteamID64:   conversionStep{convert: SID64FromAID},
+		fromSteamID64: conversionStep{convert: AIDFromSID64},
+	},
+	{
+		Format:      steamIDFormatSID2,
+		Token:       "SID2",
+		Family:      conversionFamilyAccount,
+		Example:     "STEAM_1:0:11101",
+		toSteamID64: conversionStep{convert: SID64FromSID2},
+		fromSteamID64: conversionStep{convertWithOptions: func(value string, opts conversionOptions) ConversionResult {
+			return SID2FromSID64WithUniverse(value, opts.SID2Universe)
+		}},
+	},
+	{
+		Format:        steamIDFormatSID3,
+		Token:         "SID3",
+		Family:        conversionFamilyAccount,
+		Example:       "[U:1:22202]",
+		toSteamID64:   conversionStep{convert: SID64FromSID3},
+		fromSteamID64: conversionStep{convert: SID3FromSID64},
+	},
+	{
+		Format:        steamIDFormatHex,
+		Token:         "Hex",
+		Family:        conversionFamilyAccount,
+		Example:       "steam:1100001000056ba",
+		toSteamID64:   conversionStep{convert: SID64FromHex},
+		fromSteamID64: conversionStep{convert: HexFromSID64},
+	},
+	{
+		Format:        steamIDFormatURL,
+		Token:         "URL",
+		Family:        conversionFamilyAccount,
+		Example:       ProfileURLPrefix + "76561197960287930",
+		toSteamID64:   conversionStep{convert: SID64FromProfileURL},
+		fromSteamID64: conversionStep{convert: ProfileURLFromSID64},
+	},
+	{
+		Format:        steamIDFormatGID64,
+		Token:         "GID64",
+		Family:        conversionFamilyGroup,
+		Example:       "103582791429521412",
+		toSteamID64:   conversionStep{convert: GID64FromGID64},
+		fromSteamID64: conversionStep{convert: GID64FromGID64},
+	},
+	{
+		Format:        steamIDFormatClanID,
+		Token:         "ClanID",
+		Family:        conversionFamilyGroup,
+		Example:       "4",
+		toSteamID64:   conversionStep{convert: GID64FromClanID},
+		fromSteamID64: conversionStep{convert: ClanIDFromGID64},
+	},
+	{
+		Format:        steamIDFormatGID3,
+		Token:         "GID3",
+		Family:        conversionFamilyGroup,
+		Example:       "[g:1:4]",
+		toSteamID64:   conversionStep{convert: GID64FromGID3},
+		fromSteamID64: conversionStep{convert: GID3FromGID64},
+	},
+})
+
+// labelConversionSteps names the formats each step converts between, for traces.
+// Both families normalize through their 64-bit ID.
+func labelConversionSteps(specs []steamIDFormatSpec) []steamIDFormatSpec {
+	for i := range specs {
+		base := steamIDFormatSID64
+		if specs[i].Family == conversionFamilyGroup {
+			base = steamIDFormatGID64
+		}
+		specs[i].toSteamID64.from, specs[i].toSteamID64.to = specs[i].Format, base
+		specs[i].fromSteamID64.from, specs[i].fromSteamID64.to = base, specs[i].Format
+	}
+
+	return specs
+}
+
+func lookupSteamIDFormat(name string) (steamIDFormatSpec, bool) {
+	for _, spec := range steamIDFormatRegistry {
+		if strings.EqualFold(name, string(spec.Format)) || strings.EqualFold(name, spec.Token) {
+			return spec, true
+		}
+	}
+
+	return steamIDFormatSpec{}, false
+}
+
+func mustSteamIDFormat(format steamIDFormat) steamIDFormatSpec {
+	spec, ok := lookupSteamIDFormat(string(format))
+	if !ok {
+		panic("unregistered SteamID format: " + string(format))
+	}
+
+	return spec
+}
+
+func supportedFormatNames() string {
+	names := make([]string, 0, len(steamIDFormatRegistry))
+	for _, spec := range steamIDFormatRegistry {
+		names = append(names, string(spec.Format))
+	}
+
+	return strings.Join(names, ", ")
+}
+
+func conversionTargetNames(from steamIDFormatSpec) string {
+	names := make([]string, 0, len(steamIDFormatRegistry))
+	for _, spec := range steamIDFormatRegistry {
+		if spec.Family == from.Family {
+			names = append(names, string(spec.Format))
+		}
+	}
+
+	return strings.Join(names, ", ")
+}
+
+func newConversionConfig(from, to steamIDFormatSpec) conversionHandlerConfig {
+	toStep := to.fromSteamID64
+	toStep.errorContext = steamID64ErrorContext
+
+	return conversionHandlerConfig{
+		RequestLabel:     from.Token + "to" + to.Token,
+		BatchLabel:       from.Token + "->" + to.Token,
+		SourceFormat:     from.Format,
+		Steps:            []conversionStep{from.toSteamID64, toStep},
+		UsesSID2Universe: to.Format == steamIDFormatSID2,
+	}
+}
+
+// newAutoConversionConfig converts inputs of any detectable format to one target.
+// Detected inputs are normalized to SteamID64 first, so group targets accept
+// clan SteamID64 and [g:1:N] inputs as well.
+func newAutoConversionConfig(to steamIDFormatSpec) conversionHandlerConfig {
+	toStep := to.fromSteamID64
+	toStep.errorContext = steamID64ErrorContext
+
+	return conversionHandlerConfig{
+		RequestLabel:     "autoto" + to.Token,
+		BatchLabel:       "auto->" + to.Token,
+		SourceFormat:     steamIDFormatAuto,
+		Steps:            []conversionStep{toStep},
+		UsesSID2Universe: to.Format == steamIDFormatSID2,
+	}
+}
+
+func conversionConfigFor(from, to steamIDFormat) conversionHandlerConfig {
+	return newConversionConfig(mustSteamIDFormat(from), mustSteamIDFormat(to))
+}
+
+// resolveConversionPair validates a from/to pair by name and returns a localized
+// message that names the offending format or the valid targets.
+func resolveConversionPair(fromName, toName, lang string) (conversionHandlerConfig, SteamIDError, string) {
+	to, ok := lookupSteamIDFormat(toName)
+	if !ok {
+		return conversionHandlerConfig{}, ErrorUnsupportedConversion, msgf("unknown_format", lang, toName, supportedFormatNames())
+	}
+	if strings.EqualFold(fromName, string(steamIDFormatAuto)) {
+		return newAutoConversionConfig(to), ErrorNone, ""
+	}
+	from, ok := lookupSteamIDFormat(fromName)
+	if !ok {
+		return conversionHandlerConfig{}, ErrorUnsupportedConversion, msgf("unknown_format", lang, fromName, string(steamIDFormatAuto)+", "+supportedFormatNames())
+	}
+	if from.Family != to.Family {
+		return conversionHandlerConfig{}, ErrorUnsupportedConversion, msgf("unsupported_conversion_pair", lang, from.Format, to.Format, from.Format, conversionTargetNames(from))
+	}
+
+	return newConversionConfig(from, to), ErrorNone, ""
+}
+
+// conversionRoutes returns every fixed conversion route. The registry never
+// changes, so the routes are derived once and shared; callers must not modify them.
+var conversionRoutes = sync.OnceValue(func() []conversionRoute {
+	routes := make([]conversionRoute, 0, len(steamIDFormatRegistry)*len(steamIDFormatRegistry))
+	for _, from := range steamIDFormatRegistry {
+		for _, to := range steamIDFormatRegistry {
+			if from.Format == to.Format || from.Family != to.Family {
+				continue
+			}
+
+			routes = append(routes, conversionRoute{
+				Path:   "/" + from.Token + "to" + to.Token,
+				From:   from,
+				To:     to,
+				Config: newConversionConfig(from, to),
+			})
+		}
+	}
+
+	return routes
+})
@@ -0,0 +1,161 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestConversionRoutesCoverEveryPairWithinAFamily(t *testing.T) {
+	t.Parallel()
+
+	routes := conversionRoutes()
+	if len(routes) != 36 {
+		t.Fatalf("expected 36 conversion routes, got %d", len(routes))
+	}
+
+	for _, route := range routes {
+		route := route
+		t.Run(route.Path, func(t *testing.T) {
+			t.Parallel()
+
+			result := runConversionSteps(context.Background(), route.From.Example, "en", conversionOptions{SID2Universe: "1"}, route.Config.Steps)
+			if result.Error != ErrorNone {
+				t.Fatalf("expected example %q to convert, got %q", route.From.Example, result.Error)
+			}
+			if result.Value != route.To.Example {
+				t.Fatalf("expected %q, got %q", route.To.Example, result.Value)
+			}
+			base := mustSteamIDFormat(steamIDFormatSID64)
+			if route.From.Family == conversionFamilyGroup {
+				base = mustSteamIDFormat(steamIDFormatGID64)
+			}
+			if result.SteamID64 != base.Example {
+				t.Fatalf("expected the normalized SteamID64 %q, got %q", base.Example, result.SteamID64)
+			}
+		})
+	}
+}
+
+func TestNewHandlerMuxServesDerivedRoutes(t *testing.T) {
+	mux := newHandlerMux(false)
+
+	testCases := []struct {
+		path string
+		want string
+	}{
+		{path: "/SID2toSID3?steamid=STEAM_1:0:11101", want: "[U:1:22202]"},
+		{path: "/SID3toSID2?steamid=[U:1:22202]&universe=0", want: "STEAM_0:0:11101"},
+		{path: "/AIDtoSID2?steamid=22202", want: "STEAM_1:0:11101"},
+		{path: "/AIDtoSID3?steamid=22202", want: "[U:1:22202]"},
+		{path: EndpointConvert + "?from=sid2&to=aid&steamid=STEAM_0:1:11101", want: "22203"},
+		{path: EndpointConvert + "?from=SID3&to=SID3&steamid=[U:1:22202:1]", want: "[U:1:22202]"},
+	}
+
+	for _, tc := range testCases {
+		req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+		rec := httptest.NewRecorder()
+
+		mux.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: expected status %d, got %d (%s)", tc.path, http.StatusOK, rec.Code, rec.Body.String())
+		}
+		if body := rec.Body.String(); body != tc.want {
+			t.Fatalf("%s: expected %q, got %q", tc.path, tc.want, body)
+		}
+	}
+}
+
+func TestHandleConvertRejectsUnknownPairs(t *testing.T) {
+	testCases := []struct {
+		name     string
+		query    string
+		wantBody string
+	}{
+		{
+			name:     "unknown source format",
+			query:    "?from=sid9&to=aid&steamid=1",
+			wantBody: "unknown format \"sid9\" (supported: auto, sid64, aid, sid2, sid3, hex, url, gid64, clanid, gid3)",
+		},
+		{
+			name:     "cross-family pair",
+			query:    "?from=sid2&to=gid3&steamid=STEAM_1:0:1",
+			wantBody: "conversion from sid2 to gid3 is not supported (sid2 converts to: sid64, aid, sid2, sid3, hex, url)",
+		},
+		{
+			name:     "missing target",
+			query:    "?from=sid2&steamid=STEAM_1:0:1",
+			wantBody: "from and to (or targets) parameters required",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, EndpointConvert+tc.query, nil)
+			rec := httptest.NewRecorder()
+
+			HandleConvert(rec, req)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+			}
+			if body := rec.Body.String(); body != tc.wantBody {
+				t.Fatalf("unexpected body %q", body)
+			}
+		})
+	}
+}
+
+func TestHandleConvertAutoDetectsEachBatchItem(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodGet, EndpointConvert+"?from=auto&to=sid64&steamid=STEAM_1:0:11101,22202,[U:1:22202],steam:1100001000056ba,bogus", nil)
+	rec := httptest.NewRecorder()
+
+	HandleConvert(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	want := "\"SteamIDTools\"\n{\n" +
+		"    \"STEAM_1:0:11101\"\n    {\n        \"format\" \"sid2\"\n        \"value\" \"76561197960287930\"\n    }\n" +
+		"    \"22202\"\n    {\n        \"format\" \"aid\"\n        \"value\" \"76561197960287930\"\n    }\n" +
+		"    \"[U:1:22202]\"\n    {\n        \"format\" \"sid3\"\n        \"value\" \"76561197960287930\"\n    }\n" +
+		"    \"steam:1100001000056ba\"\n    {\n        \"format\" \"hex\"\n        \"value\" \"76561197960287930\"\n    }\n" +
+		"    \"bogus\"\n    {\n        \"format\" \"unknown\"\n        \"value\" \"ERROR: Invalid SteamID format provided\"\n    }\n" +
+		"}"
+	if body := rec.Body.String(); body != want {
+		t.Fatalf("unexpected body %q", body)
+	}
+}
+
+func TestHandleConvertAutoBatchJSONReportsFormat(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodGet, EndpointConvert+"?from=auto&to=aid&format=json&steamid=76561197960287930,[U:1:x]", nil)
+	rec := httptest.NewRecorder()
+
+	HandleConvert(rec, req)
+
+	want := `[{"input":"76561197960287930","format":"sid64","value":"22202"},` +
+		`{"input":"[U:1:x]","format":"sid3","error":"invalid_characters","message":"Contains invalid characters"}]`
+	if body := rec.Body.String(); body != want {
+		t.Fatalf("unexpected body %q", body)
+	}
+}
+
+func TestHandleConvertAutoSingleInput(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodGet, EndpointConvert+"?from=auto&to=gid3&steamid=103582791429521412", nil)
+	rec := httptest.NewRecorder()
+
+	HandleConvert(rec, req)
+
+	if body := rec.Body.String(); rec.Code != http.StatusOK || body != "[g:1:4]" {
+		t.Fatalf("unexpected response %d %q", rec.Code, body)
+	}
+}
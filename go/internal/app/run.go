@@ -1,15 +1,36 @@
 package app
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	httpSwagger "github.com/swaggo/http-swagger/v2"
 	docs "steamid-service/internal/app/docs"
 )
 
+// documentedConversionHandlers keeps the Swagger-annotated handlers wired to their routes;
+// every other registry pair is served by a generic handler.
+var documentedConversionHandlers = map[string]http.HandlerFunc{
+	EndpointSID64toAID:    HandleSteamID64ToAccountID,
+	EndpointSID64toSID2:   HandleSteamID64ToSteamID2,
+	EndpointSID64toSID3:   HandleSteamID64ToSteamID3,
+	EndpointAIDtoSID64:    HandleAccountIDToSteamID64,
+	EndpointSID2toSID64:   HandleSteamID2ToSteamID64,
+	EndpointSID3toSID64:   HandleSteamID3ToSteamID64,
+	EndpointGID64toClanID: HandleGroupID64ToClanID,
+	EndpointGID64toGID3:   HandleGroupID64ToGroupID3,
+	EndpointClanIDtoGID64: HandleClanIDToGroupID64,
+	EndpointClanIDtoGID3:  HandleClanIDToGroupID3,
+	EndpointGID3toGID64:   HandleGroupID3ToGroupID64,
+	EndpointGID3toClanID:  HandleGroupID3ToClanID,
+}
+
 func newHandlerMux(debugMode bool) *http.ServeMux {
 	mux := http.NewServeMux()
 
@@ -22,13 +43,25 @@ func newHandlerMux(debugMode bool) *http.ServeMux {
 	mux.Handle("/swagger/", httpSwagger.Handler(
 		httpSwagger.URL("/swagger/doc.json"),
 	))
-	mux.Handle(EndpointSID64toAID, http.HandlerFunc(HandleSteamID64ToAccountID))
-	mux.Handle(EndpointSID64toSID2, http.HandlerFunc(HandleSteamID64ToSteamID2))
-	mux.Handle(EndpointSID64toSID3, http.HandlerFunc(HandleSteamID64ToSteamID3))
-	mux.Handle(EndpointAIDtoSID64, http.HandlerFunc(HandleAccountIDToSteamID64))
-	mux.Handle(EndpointSID2toSID64, http.HandlerFunc(HandleSteamID2ToSteamID64))
-	mux.Handle(EndpointSID3toSID64, http.HandlerFunc(HandleSteamID3ToSteamID64))
+	for _, route := range conversionRoutes() {
+		handler, ok := documentedConversionHandlers[route.Path]
+		if !ok {
+			handler = newConversionHandler(route.Config)
+		}
+		mux.Handle(route.Path, handler)
+	}
+	mux.Handle(EndpointConvert, http.HandlerFunc(HandleConvert))
+	mux.Handle(EndpointStream, http.HandlerFunc(HandleStream))
+	mux.Handle(EndpointCSV, http.HandlerFunc(HandleCSV))
+	mux.Handle(EndpointJobs, http.HandlerFunc(HandleJob))
+	mux.Handle(EndpointJobs+"/", http.HandlerFunc(HandleJob))
+	mux.Handle(EndpointErrors, http.HandlerFunc(HandleErrors))
+	mux.Handle(EndpointErrors+"/", http.HandlerFunc(HandleErrorKey))
+	mux.Handle(EndpointDescribe, http.HandlerFunc(HandleDescribe))
 	mux.Handle(EndpointHealth, http.HandlerFunc(HandleHealth))
+	if !metricsSeparate() {
+		mux.Handle(EndpointMetrics, http.HandlerFunc(HandleMetrics))
+	}
 	mux.Handle("/", http.HandlerFunc(HandleNotFound))
 
 	return mux
@@ -59,48 +92,69 @@ func publicHost(host string) string {
 }
 
 func startupEndpoints(baseURL, sid2Example string) []endpointRegistration {
-	return []endpointRegistration{
-		{
-			Name:       "swagger",
-			Path:       "/swagger/index.html",
-			ExampleURL: baseURL + "/swagger/index.html",
-		},
-		{
-			Name:       "sid64_to_aid",
-			Path:       EndpointSID64toAID,
-			ExampleURL: fmt.Sprintf("%s%s?steamid=76561197960287930", baseURL, EndpointSID64toAID),
+	routes := conversionRoutes()
+	endpoints := make([]endpointRegistration, 0, len(routes)+8)
+	endpoints = append(endpoints, endpointRegistration{
+		Name:       "swagger",
+		Path:       "/swagger/index.html",
+		ExampleURL: baseURL + "/swagger/index.html",
+	})
+
+	for _, route := range routes {
+		example := route.From.Example
+		if route.From.Format == steamIDFormatSID2 {
+			example = sid2Example
+		}
+
+		endpoints = append(endpoints, endpointRegistration{
+			Name:       string(route.From.Format) + "_to_" + string(route.To.Format),
+			Path:       route.Path,
+			ExampleURL: fmt.Sprintf("%s%s?steamid=%s", baseURL, route.Path, example),
+		})
+	}
+
+	return append(endpoints,
+		endpointRegistration{
+			Name:       "convert",
+			Path:       EndpointConvert,
+			ExampleURL: fmt.Sprintf("%s%s?from=sid2&to=sid3&steamid=%s", baseURL, EndpointConvert, sid2Example),
 		},
-		{
-			Name:       "sid64_to_sid2",
-			Path:       EndpointSID64toSID2,
-			ExampleURL: fmt.Sprintf("%s%s?steamid=76561197960287930", baseURL, EndpointSID64toSID2),
+		endpointRegistration{
+			Name:       "stream",
+			Path:       EndpointStream,
+			ExampleURL: fmt.Sprintf("%s%s?from=auto&to=sid64", baseURL, EndpointStream),
 		},
-		{
-			Name:       "sid64_to_sid3",
-			Path:       EndpointSID64toSID3,
-			ExampleURL: fmt.Sprintf("%s%s?steamid=76561197960287930", baseURL, EndpointSID64toSID3),
+		endpointRegistration{
+			Name:       "csv",
+			Path:       EndpointCSV,
+			ExampleURL: fmt.Sprintf("%s%s?columns=steamid&to=sid64", baseURL, EndpointCSV),
 		},
-		{
-			Name:       "aid_to_sid64",
-			Path:       EndpointAIDtoSID64,
-			ExampleURL: fmt.Sprintf("%s%s?steamid=22202", baseURL, EndpointAIDtoSID64),
+		endpointRegistration{
+			Name:       "jobs",
+			Path:       EndpointJobs + "/{id}",
+			ExampleURL: fmt.Sprintf("%s%s/{id}", baseURL, EndpointJobs),
 		},
-		{
-			Name:       "sid2_to_sid64",
-			Path:       EndpointSID2toSID64,
-			ExampleURL: fmt.Sprintf("%s%s?steamid=%s", baseURL, EndpointSID2toSID64, sid2Example),
+		endpointRegistration{
+			Name:       "errors",
+			Path:       EndpointErrors,
+			ExampleURL: baseURL + EndpointErrors,
 		},
-		{
-			Name:       "sid3_to_sid64",
-			Path:       EndpointSID3toSID64,
-			ExampleURL: fmt.Sprintf("%s%s?steamid=[U:1:22202]", baseURL, EndpointSID3toSID64),
+		endpointRegistration{
+			Name:       "describe",
+			Path:       EndpointDescribe,
+			ExampleURL: fmt.Sprintf("%s%s?steamid=76561197960287930", baseURL, EndpointDescribe),
 		},
-		{
+		endpointRegistration{
 			Name:       "health",
 			Path:       EndpointHealth,
 			ExampleURL: baseURL + EndpointHealth,
 		},
-	}
+		endpointRegistration{
+			Name:       "metrics",
+			Path:       EndpointMetrics,
+			ExampleURL: metricsURL(baseURL),
+		},
+	)
 }
 
 func logStartup(baseURL, host, port, backendLang, sid2Universe string, debugMode bool) {
@@ -116,6 +170,13 @@ func logStartup(baseURL, host, port, backendLang, sid2Universe string, debugMode
 		Str("backend_lang", backendLang).
 		Str("sid2_universe", sid2Universe).
 		Int("max_batch_items", appCfg.MaxBatchItems).
+		Dur("job_ttl", appCfg.JobTTL).
+		Str("job_store_dir", appCfg.JobStoreDir).
+		Str("metrics_addr", appCfg.MetricsAddr).
+		Str("config_file", appCfg.ConfigFile).
+		Str("log_level", appCfg.zerologLevel().String()).
+		Str("cors_allow_origin", appCfg.CORSAllowOrigin).
+		Str("trace_endpoint", appCfg.tracesEndpoint()).
 		Msg("service starting")
 
 	appInfoEvent().
@@ -124,8 +185,11 @@ func logStartup(baseURL, host, port, backendLang, sid2Universe string, debugMode
 		Bool("batch_processing", true).
 		Bool("keyvalue_output", true).
 		Bool("swagger_enabled", true).
+		Bool("metrics_enabled", true).
+		Bool("tracing_enabled", appCfg.tracesEndpoint() != "").
 		Str("batch_output_format", "valve-keyvalue").
 		Str("swagger_url", baseURL+"/swagger/index.html").
+		Str("metrics_url", metricsURL(baseURL)).
 		Msg("service features enabled")
 
 	appInfoEvent().
@@ -140,23 +204,67 @@ func logStartup(baseURL, host, port, backendLang, sid2Universe string, debugMode
 	}
 
 	appInfoEvent().
-		Str("shutdown_signal", "ctrl+c").
+		Strs("shutdown_signals", []string{"SIGINT", "SIGTERM"}).
+		Str("reload_signal", "SIGHUP").
+		Dur("shutdown_timeout", appCfg.ShutdownTimeout).
 		Msg("service ready")
 }
 
 func Run() error {
-	backendLangFlag := flag.String("backend-lang", appCfg.BackendLang, "Backend language (en/es)")
-	flag.Parse()
-	appCfg.BackendLang = *backendLangFlag
-	configureLogger(appCfg.Debug)
+	if len(os.Args) > 1 && os.Args[1] == "csv" {
+		if err := runCSVCommand(os.Args[2:], os.Stdin, os.Stdout); err != nil {
+			return fmt.Errorf(msgBackend("csv_failed"), err)
+		}
+		return nil
+	}
+	if len(os.Args) > 1 && os.Args[1] == "check-config" {
+		if err := runCheckConfigCommand(os.Args[2:], os.Getenv, os.Stdout); err != nil {
+			return fmt.Errorf(msgBackend("config_failed"), err)
+		}
+		return nil
+	}
+
+	args := os.Args[1:]
+	cfg, err := loadConfig(args, os.Getenv)
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	if err != nil {
+		// Report the problems in the configured language when it was valid.
+		if cfg.BackendLang != "" {
+			loadBackendMessages(cfg.BackendLang)
+		}
+		return fmt.Errorf(msgBackend("config_failed"), err)
+	}
+	appCfg = cfg
+	configureLogger(appCfg.Debug, appCfg.zerologLevel())
 	loadBackendMessages(appCfg.BackendLang)
 
+	var store jobStore = newMemoryJobStore()
+	if appCfg.JobStoreDir != "" {
+		if store, err = newFileJobStore(appCfg.JobStoreDir); err != nil {
+			return fmt.Errorf(msgBackend("job_store_failed"), err)
+		}
+	}
+	batchJobs = newJobRunner(store, appCfg.JobTTL)
+	if err := batchJobs.recoverJobs(); err != nil {
+		return fmt.Errorf(msgBackend("job_store_failed"), err)
+	}
+	stopSweep := batchJobs.sweepEvery(jobSweepInterval)
+	defer stopSweep()
+	if endpoint := appCfg.tracesEndpoint(); endpoint != "" {
+		activeTracer.Store(newTracer(newOTLPExporter(endpoint, appCfg.TraceServiceName)))
+	}
+
 	debugMode := appCfg.Debug
 	port := appCfg.Port
 	host := appCfg.Host
 
 	addr := fmt.Sprintf("%s:%s", host, port)
-	server := newHTTPServer(addr, accessLogMiddleware(newHandlerMux(debugMode)))
+	servers := []*http.Server{newHTTPServer(addr, drainMiddleware(tracingMiddleware(accessLogMiddleware(metricsMiddleware(newHandlerMux(debugMode))))))}
+	if metricsSeparate() {
+		servers = append(servers, newHTTPServer(appCfg.MetricsAddr, newMetricsMux()))
+	}
 	docs.SwaggerInfo.BasePath = "/"
 	docs.SwaggerInfo.Schemes = []string{"http"}
 	docs.SwaggerInfo.Host = fmt.Sprintf("%s:%s", publicHost(host), port)
@@ -164,10 +272,50 @@ func Run() error {
 
 	sid2Universe := appCfg.SID2Universe
 	baseURL := fmt.Sprintf("http://%s:%s", publicHost(host), port)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(signals)
 	logStartup(baseURL, host, port, appCfg.BackendLang, sid2Universe, debugMode)
-	if err := server.ListenAndServe(); err != nil {
-		return fmt.Errorf(msgBackend("server_failed"), err)
+
+	serverErrors := make(chan error, len(servers))
+	for _, server := range servers {
+		go func() { serverErrors <- server.ListenAndServe() }()
+	}
+
+	for {
+		select {
+		case err := <-serverErrors:
+			return fmt.Errorf(msgBackend("server_failed"), err)
+		case sig := <-signals:
+			if sig == syscall.SIGHUP {
+				reloadFromSignal(args)
+				continue
+			}
+			// A second signal falls back to the default handler and stops the process at once.
+			signal.Stop(signals)
+			shutdownServers(servers, signalName(sig))
+			return nil
+		}
 	}
+}
 
-	return nil
+// reloadFromSignal reads the flags, config file and environment again and
+// applies the settings that can change while serving; the listeners are untouched.
+func reloadFromSignal(args []string) {
+	cfg, err := loadConfig(args, os.Getenv)
+	if err != nil {
+		appWarnEvent().Err(err).Msg("config reload failed")
+		return
+	}
+
+	changed, restart := reloadConfig(cfg)
+	event := appInfoEvent()
+	if len(restart) > 0 {
+		event = appWarnEvent()
+	}
+	event.
+		Str("config_file", cfg.ConfigFile).
+		Strs("changed", changed).
+		Strs("restart_required", restart).
+		Msg("config reloaded")
 }
@@ -0,0 +1,109 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// drainState tracks in-flight requests and whether the service is shutting down.
+type drainState struct {
+	draining atomic.Bool
+	inFlight atomic.Int64
+}
+
+var serviceDrain drainState
+
+// drainMiddleware counts in-flight requests and closes each connection after
+// its response once shutdown has begun.
+func drainMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serviceDrain.inFlight.Add(1)
+		defer serviceDrain.inFlight.Add(-1)
+
+		if serviceDrain.draining.Load() {
+			w.Header().Set("Connection", "close")
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// shutdownServers drains servers after signal: /health answers 503 from the
+// start, new connections are refused after appCfg.ShutdownDelay, and in-flight
+// requests and batch jobs get appCfg.ShutdownTimeout to finish before the
+// remaining connections are closed. It reports whether everything drained.
+func shutdownServers(servers []*http.Server, signal string) bool {
+	startedAt := time.Now()
+	serviceDrain.draining.Store(true)
+	for _, server := range servers {
+		server.SetKeepAlivesEnabled(false)
+	}
+
+	appInfoEvent().
+		Str("signal", signal).
+		Int64("in_flight_requests", serviceDrain.inFlight.Load()).
+		Int32("pending_jobs", batchJobs.pending.Load()).
+		Dur("shutdown_delay", appCfg.ShutdownDelay).
+		Dur("shutdown_timeout", appCfg.ShutdownTimeout).
+		Msg("service shutting down")
+
+	time.Sleep(appCfg.ShutdownDelay)
+
+	ctx, cancel := context.WithTimeout(context.Background(), appCfg.ShutdownTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	shutdownErrors := make([]error, len(servers))
+	for i, server := range servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			shutdownErrors[i] = server.Shutdown(ctx)
+		}()
+	}
+	wg.Wait()
+	jobsDone := batchJobs.wait(ctx)
+	if t := activeTracer.Load(); t != nil {
+		t.flushContext(ctx)
+	}
+
+	err := errors.Join(shutdownErrors...)
+	drained := err == nil && jobsDone
+	if !drained {
+		for _, server := range servers {
+			_ = server.Close()
+		}
+	}
+
+	event := appInfoEvent()
+	if !drained {
+		event = appWarnEvent()
+		if err != nil {
+			event = event.Err(err)
+		}
+	}
+	event.
+		Str("signal", signal).
+		Bool("drained", drained).
+		Int64("abandoned_requests", serviceDrain.inFlight.Load()).
+		Int32("abandoned_jobs", batchJobs.pending.Load()).
+		Int64("duration_ms", time.Since(startedAt).Milliseconds()).
+		Msg("service stopped")
+	return drained
+}
+
+// signalName names the shutdown signals the way the startup log lists them.
+func signalName(sig os.Signal) string {
+	switch sig {
+	case os.Interrupt:
+		return "SIGINT"
+	case syscall.SIGTERM:
+		return "SIGTERM"
+	}
+	return sig.String()
+}
@@ -0,0 +1,157 @@
+package app
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// startDrainServer serves handler behind drainMiddleware on a local port.
+func startDrainServer(t *testing.T, handler http.Handler) (*http.Server, string) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	server := newHTTPServer(listener.Addr().String(), drainMiddleware(handler))
+	go func() { _ = server.Serve(listener) }()
+	return server, "http://" + listener.Addr().String()
+}
+
+// Not parallel: shutdown changes process-wide state.
+func TestShutdownServersDrainsInFlightRequests(t *testing.T) {
+	previousDelay, previousTimeout := appCfg.ShutdownDelay, appCfg.ShutdownTimeout
+	appCfg.ShutdownDelay = 0
+	appCfg.ShutdownTimeout = 5 * time.Second
+	t.Cleanup(func() {
+		appCfg.ShutdownDelay, appCfg.ShutdownTimeout = previousDelay, previousTimeout
+		serviceDrain.draining.Store(false)
+	})
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	server, baseURL := startDrainServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(entered)
+		<-release
+		writePlainTextBody(w, "done")
+	}))
+
+	responses := make(chan string, 1)
+	go func() {
+		resp, err := http.Get(baseURL + EndpointSID64toAID)
+		if err != nil {
+			responses <- err.Error()
+			return
+		}
+		defer func() { _ = resp.Body.Close() }()
+		body, _ := io.ReadAll(resp.Body)
+		responses <- string(body)
+	}()
+	<-entered
+
+	drained := make(chan bool, 1)
+	go func() { drained <- shutdownServers([]*http.Server{server}, "SIGTERM") }()
+	for !serviceDrain.draining.Load() {
+		time.Sleep(time.Millisecond)
+	}
+
+	health := httptest.NewRecorder()
+	HandleHealth(health, httptest.NewRequest(http.MethodGet, EndpointHealth, nil))
+	if health.Code != http.StatusServiceUnavailable || health.Header().Get(ErrorHeader) != "service_unavailable" ||
+		health.Body.String() != "UNHEALTHY: Shutting down\n" {
+		t.Fatalf("unexpected health while draining %d %q", health.Code, health.Body.String())
+	}
+
+	close(release)
+	if got := <-responses; got != "done" {
+		t.Fatalf("in-flight request was cut off: %q", got)
+	}
+	if !<-drained {
+		t.Fatalf("expected a clean drain")
+	}
+	if _, err := http.Get(baseURL + EndpointHealth); err == nil {
+		t.Fatalf("expected new connections to be refused after shutdown")
+	}
+}
+
+// Not parallel: shutdown changes process-wide state.
+func TestShutdownServersFailsHealthDuringDelay(t *testing.T) {
+	previousDelay, previousTimeout := appCfg.ShutdownDelay, appCfg.ShutdownTimeout
+	appCfg.ShutdownDelay = 300 * time.Millisecond
+	appCfg.ShutdownTimeout = time.Second
+	t.Cleanup(func() {
+		appCfg.ShutdownDelay, appCfg.ShutdownTimeout = previousDelay, previousTimeout
+		serviceDrain.draining.Store(false)
+	})
+
+	server, baseURL := startDrainServer(t, newHandlerMux(false))
+	drained := make(chan bool, 1)
+	go func() { drained <- shutdownServers([]*http.Server{server}, "SIGTERM") }()
+	for !serviceDrain.draining.Load() {
+		time.Sleep(time.Millisecond)
+	}
+
+	// A poller on the network must see the 503 while the listener is still open.
+	resp, err := http.Get(baseURL + EndpointHealth)
+	if err != nil {
+		t.Fatalf("listener closed before the shutdown delay ended: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable || !resp.Close {
+		t.Fatalf("unexpected health during the shutdown delay: %d %v", resp.StatusCode, resp.Header)
+	}
+
+	if !<-drained {
+		t.Fatalf("expected a clean drain")
+	}
+	if _, err := http.Get(baseURL + EndpointHealth); err == nil {
+		t.Fatalf("expected new connections to be refused after the delay")
+	}
+}
+
+func TestDefaultShutdownFitsDockerStopGrace(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultConfig()
+	if cfg.ShutdownDelay <= 0 || cfg.ShutdownDelay+cfg.ShutdownTimeout >= 10*time.Second {
+		t.Fatalf("unexpected shutdown defaults delay=%s timeout=%s", cfg.ShutdownDelay, cfg.ShutdownTimeout)
+	}
+}
+
+// Not parallel: shutdown changes process-wide state.
+func TestShutdownServersStopsAtDeadline(t *testing.T) {
+	previousDelay, previousTimeout := appCfg.ShutdownDelay, appCfg.ShutdownTimeout
+	appCfg.ShutdownDelay = 0
+	appCfg.ShutdownTimeout = 50 * time.Millisecond
+	t.Cleanup(func() {
+		appCfg.ShutdownDelay, appCfg.ShutdownTimeout = previousDelay, previousTimeout
+		serviceDrain.draining.Store(false)
+	})
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	server, baseURL := startDrainServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(entered)
+		<-release
+	}))
+
+	go func() {
+		if resp, err := http.Get(baseURL + EndpointSID64toAID); err == nil {
+			_ = resp.Body.Close()
+		}
+	}()
+	<-entered
+
+	startedAt := time.Now()
+	if shutdownServers([]*http.Server{server}, "SIGINT") {
+		t.Fatalf("expected the stuck request to prevent a clean drain")
+	}
+	if elapsed := time.Since(startedAt); elapsed > time.Second {
+		t.Fatalf("shutdown overran its deadline: %s", elapsed)
+	}
+}
@@ -0,0 +1,197 @@
+package app
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	streamFlushItems    = 256
+	streamFlushInterval = 250 * time.Millisecond
+	// streamIOTimeout replaces the server read/write timeouts after every flush,
+	// so a stream only fails when the client stalls rather than after 10 seconds.
+	streamIOTimeout    = 30 * time.Second
+	maxStreamLineBytes = 4096
+)
+
+func handleStream(w http.ResponseWriter, r *http.Request) {
+	lang := getLang(r)
+	logDebug(r, "stream request: %v", r.URL.RawQuery)
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, r, ErrorMissingParameter, msg("stream_body_required", lang), "stream request without POST body")
+		return
+	}
+
+	query := r.URL.Query()
+	from := query.Get("from")
+	if from == "" {
+		from = string(steamIDFormatAuto)
+	}
+	to := query.Get("to")
+	if to == "" {
+		writeErrorResponse(w, r, ErrorMissingParameter, msg("target_param_required", lang), "to query parameter missing")
+		return
+	}
+
+	cfg, pairErr, message := resolveConversionPair(from, to, lang)
+	if !pairErr.IsValid() {
+		writeErrorResponse(w, r, pairErr, message, from+"->"+to)
+		return
+	}
+
+	opts, optsErr := resolveConversionOptions(r, cfg)
+	if !optsErr.IsValid() {
+		writeErrorResponse(w, r, optsErr, "", "sid2 universe override rejected")
+		return
+	}
+
+	streamConversion(w, r, lang, opts, cfg)
+}
+
+// streamConversion converts the request body line by line and writes one NDJSON
+// item per input without buffering the batch. It stops as soon as the client
+// disconnects or a write fails.
+func streamConversion(w http.ResponseWriter, r *http.Request, lang string, opts conversionOptions, cfg conversionHandlerConfig) {
+	flusher := newStreamFlusher(w)
+	defer flusher.stop()
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	setCORSHeader(w)
+	w.WriteHeader(http.StatusOK)
+
+	scanner := bufio.NewScanner(r.Body)
+	scanner.Buffer(make([]byte, 0, 1024), maxStreamLineBytes)
+	encoder := json.NewEncoder(flusher)
+	encoder.SetEscapeHTML(false)
+
+	ctx, span := startItemsSpan(r.Context(), "stream")
+	defer span.finish()
+	span.setAttributes(stringAttribute("steamidtools.conversion", cfg.BatchLabel))
+
+	items := 0
+	defer func() { span.setAttributes(intAttribute("steamidtools.batch.items", items)) }()
+	for scanner.Scan() {
+		if r.Context().Err() != nil {
+			appInfof("stream conversion cancelled: conversion=%s items=%d remote_addr=%s", cfg.BatchLabel, items, r.RemoteAddr)
+			return
+		}
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		format, result := cfg.convertInput(ctx, line, lang, opts)
+		item := localizeBatchItem(BatchItemResult{
+			Input:  line,
+			Format: format,
+			Value:  result.Value,
+			Error:  result.Error,
+		}, lang)
+		if err := encoder.Encode(item); err != nil {
+			appWarnf("stream conversion aborted: conversion=%s items=%d error=%v remote_addr=%s", cfg.BatchLabel, items, err, r.RemoteAddr)
+			return
+		}
+
+		items++
+		flusher.written()
+	}
+
+	if err := scanner.Err(); err != nil && r.Context().Err() == nil {
+		// Headers are already sent, so a read failure becomes the final NDJSON line.
+		_ = encoder.Encode(ErrorResponse{Error: ErrorInvalidFormat.Key(), Message: msg("batch_parse_failed", lang)})
+		appWarnf("stream conversion input rejected: conversion=%s items=%d error=%v remote_addr=%s", cfg.BatchLabel, items, err, r.RemoteAddr)
+	}
+	flusher.flush()
+
+	appInfof("stream conversion processed: conversion=%s items=%d remote_addr=%s", cfg.BatchLabel, items, r.RemoteAddr)
+}
+
+// streamFlusher flushes a streaming response every streamFlushItems writes or
+// streamFlushInterval, whichever comes first, and pushes the I/O deadlines forward.
+// The interval runs on its own ticker so results still go out while the handler
+// is blocked reading slow input; responses must be written through the flusher,
+// which serializes those writes with the ticker's flushes.
+type streamFlusher struct {
+	mu         sync.Mutex
+	w          http.ResponseWriter
+	controller *http.ResponseController
+	pending    int
+	done       chan struct{}
+	stopped    sync.WaitGroup
+}
+
+func newStreamFlusher(w http.ResponseWriter) *streamFlusher {
+	controller := http.NewResponseController(w)
+	// HTTP/1 servers stop reading the body once the response starts unless full duplex is on.
+	_ = controller.EnableFullDuplex()
+
+	flusher := &streamFlusher{w: w, controller: controller, done: make(chan struct{})}
+	flusher.extendDeadlines()
+	flusher.stopped.Add(1)
+	go flusher.flushEvery(streamFlushInterval)
+	return flusher
+}
+
+func (f *streamFlusher) flushEvery(interval time.Duration) {
+	defer f.stopped.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			f.mu.Lock()
+			if f.pending > 0 {
+				f.flushLocked()
+			}
+			f.mu.Unlock()
+		case <-f.done:
+			return
+		}
+	}
+}
+
+func (f *streamFlusher) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.w.Write(p)
+}
+
+func (f *streamFlusher) written() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.pending++
+	if f.pending >= streamFlushItems {
+		f.flushLocked()
+	}
+}
+
+func (f *streamFlusher) flush() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.flushLocked()
+}
+
+// stop ends the interval flushes; the handler must not return before it does.
+func (f *streamFlusher) stop() {
+	close(f.done)
+	f.stopped.Wait()
+}
+
+func (f *streamFlusher) flushLocked() {
+	_ = f.controller.Flush()
+	f.extendDeadlines()
+	f.pending = 0
+}
+
+func (f *streamFlusher) extendDeadlines() {
+	deadline := time.Now().Add(streamIOTimeout)
+	_ = f.controller.SetReadDeadline(deadline)
+	_ = f.controller.SetWriteDeadline(deadline)
+}
@@ -0,0 +1,150 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandleStreamWritesOneResultPerLine(t *testing.T) {
+	t.Parallel()
+
+	body := "76561197960287930\n\nSTEAM_1:0:11101\r\nbogus\n"
+	req := httptest.NewRequest(http.MethodPost, EndpointStream+"?to=aid", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	HandleStream(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if contentType := rec.Header().Get("Content-Type"); contentType != "application/x-ndjson" {
+		t.Fatalf("unexpected content type %q", contentType)
+	}
+
+	want := `{"input":"76561197960287930","format":"sid64","value":"22202"}` + "\n" +
+		`{"input":"STEAM_1:0:11101","format":"sid2","value":"22202"}` + "\n" +
+		`{"input":"bogus","error":"invalid_format","message":"Invalid SteamID format provided"}` + "\n"
+	if got := rec.Body.String(); got != want {
+		t.Fatalf("unexpected body %q", got)
+	}
+	if !rec.Flushed {
+		t.Fatal("expected the stream to be flushed")
+	}
+}
+
+func TestHandleStreamFlushesWhileWaitingForInput(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(HandleStream))
+	defer server.Close()
+
+	body, input := io.Pipe()
+	defer func() { _ = input.Close() }()
+	req, err := http.NewRequest(http.MethodPost, server.URL+EndpointStream+"?from=sid64&to=aid", body)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+
+	responses := make(chan *http.Response, 1)
+	go func() {
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Errorf("stream request: %v", err)
+			close(responses)
+			return
+		}
+		responses <- resp
+	}()
+	if _, err := io.WriteString(input, "76561197960287930\n"); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+
+	// The body stays open, so the result can only arrive through the interval flush.
+	lines := make(chan string, 1)
+	go func() {
+		resp, ok := <-responses
+		if !ok {
+			return
+		}
+		defer func() { _ = resp.Body.Close() }()
+		line, _ := bufio.NewReader(resp.Body).ReadString('\n')
+		lines <- line
+	}()
+	select {
+	case line := <-lines:
+		if line != `{"input":"76561197960287930","format":"sid64","value":"22202"}`+"\n" {
+			t.Fatalf("unexpected first line %q", line)
+		}
+	case <-time.After(10 * streamFlushInterval):
+		t.Fatal("result was not flushed while the handler waited for more input")
+	}
+}
+
+func TestHandleStreamStopsWhenClientDisconnects(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	req := httptest.NewRequest(http.MethodPost, EndpointStream+"?from=sid64&to=aid", strings.NewReader("76561197960287930\n76561197960287931\n"))
+	req = req.WithContext(ctx)
+	rec := httptest.NewRecorder()
+
+	HandleStream(rec, req)
+
+	if got := rec.Body.String(); got != "" {
+		t.Fatalf("expected no items after disconnect, got %q", got)
+	}
+}
+
+func TestHandleStreamEndsWithErrorLineOnOversizedInput(t *testing.T) {
+	t.Parallel()
+
+	body := "76561197960287930\n" + strings.Repeat("7", maxStreamLineBytes+1) + "\n"
+	req := httptest.NewRequest(http.MethodPost, EndpointStream+"?to=aid", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	HandleStream(rec, req)
+
+	lines := strings.Split(strings.TrimSuffix(rec.Body.String(), "\n"), "\n")
+	if len(lines) != 2 || lines[1] != `{"error":"invalid_format","message":"failed to parse batch input"}` {
+		t.Fatalf("unexpected stream %q", rec.Body.String())
+	}
+}
+
+func TestHandleStreamRejectsInvalidRequests(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		method   string
+		query    string
+		wantBody string
+	}{
+		{name: "get", method: http.MethodGet, query: "?to=aid", wantBody: "stream requires a POST body with one SteamID per line"},
+		{name: "missing target", method: http.MethodPost, query: "", wantBody: "to parameter required"},
+		{name: "cross-family", method: http.MethodPost, query: "?from=sid2&to=gid3", wantBody: "conversion from sid2 to gid3 is not supported (sid2 converts to: sid64, aid, sid2, sid3, hex, url)"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(tc.method, EndpointStream+tc.query, strings.NewReader("76561197960287930\n"))
+			rec := httptest.NewRecorder()
+
+			HandleStream(rec, req)
+
+			if rec.Code != http.StatusBadRequest || rec.Body.String() != tc.wantBody {
+				t.Fatalf("unexpected response %d %q", rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
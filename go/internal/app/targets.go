@@ -0,0 +1,199 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"steamid-service/pkg/keyvalues"
+)
+
+// conversionTarget is one entry of the targets parameter.
+type conversionTarget struct {
+	Format steamIDFormat
+	Config conversionHandlerConfig
+}
+
+// targetItem holds one input converted to every requested target, in the order
+// of the targets parameter. Each result reuses BatchItemResult with Input unset.
+type targetItem struct {
+	Input   string
+	Format  steamIDFormat
+	Results []BatchItemResult
+}
+
+// resolveConversionTargets validates every comma-separated target against from
+// with the same rules as a single from/to pair. Repeated targets are dropped.
+func resolveConversionTargets(fromName, targetsParam, lang string) ([]conversionTarget, SteamIDError, string) {
+	var targets []conversionTarget
+	seen := make(map[steamIDFormat]struct{})
+	for _, name := range strings.Split(targetsParam, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		cfg, pairErr, message := resolveConversionPair(fromName, name, lang)
+		if !pairErr.IsValid() {
+			return nil, pairErr, message
+		}
+		spec, _ := lookupSteamIDFormat(name)
+		if _, exists := seen[spec.Format]; exists {
+			continue
+		}
+		seen[spec.Format] = struct{}{}
+		targets = append(targets, conversionTarget{Format: spec.Format, Config: cfg})
+	}
+
+	if len(targets) == 0 {
+		return nil, ErrorMissingParameter, msg("targets_empty", lang)
+	}
+	return targets, ErrorNone, ""
+}
+
+// handleMultiTargetConversion answers /convert?targets=... with one section per
+// input, so a single or comma-separated steamid and a POST body all get the
+// batch layout.
+func handleMultiTargetConversion(w http.ResponseWriter, r *http.Request, targets []conversionTarget) {
+	lang := getLang(r)
+	labels := make([]string, 0, len(targets))
+	optsCfg := conversionHandlerConfig{}
+	for _, target := range targets {
+		labels = append(labels, target.Config.BatchLabel)
+		optsCfg.UsesSID2Universe = optsCfg.UsesSID2Universe || target.Config.UsesSID2Universe
+	}
+	label := strings.Join(labels, ",")
+	logDebug(r, "multi-target request %s: %v", label, r.URL.RawQuery)
+
+	opts, optsErr := resolveConversionOptions(r, optsCfg)
+	if !optsErr.IsValid() {
+		writeErrorResponse(w, r, optsErr, "", "sid2 universe override rejected")
+		return
+	}
+
+	// Multi-target batches keep the reject semantics; mode applies to single-target batches.
+	batch := batchOptions{Mode: batchModeReject}
+	var steamids []string
+	if r.Method == http.MethodPost {
+		items, bodyErr, message := readBatchBody(w, r, lang)
+		if !bodyErr.IsValid() {
+			writeErrorResponse(w, r, bodyErr, message, "batch body rejected")
+			return
+		}
+		var parseErr SteamIDError
+		var item errorItem
+		_, maxPostItems, _ := batchLimits()
+		if steamids, parseErr, item = validateBatchItems(items, maxPostItems, &batch); !parseErr.IsValid() {
+			writeBatchParseError(w, r, lang, "batch body", parseErr, maxPostItems, item)
+			return
+		}
+	} else {
+		rawInput := r.URL.Query().Get("steamid")
+		if rawInput == "" {
+			writeErrorResponse(w, r, ErrorMissingParameter, msg("steamid_param_required", lang), "steamid query parameter missing")
+			return
+		}
+		var parseErr SteamIDError
+		var item errorItem
+		maxItems, _, _ := batchLimits()
+		if steamids, parseErr, item = parseBatchInput(rawInput, maxItems, &batch); !parseErr.IsValid() {
+			writeBatchParseError(w, r, lang, rawInput, parseErr, maxItems, item)
+			return
+		}
+	}
+
+	ctx, span := startItemsSpan(r.Context(), "batch")
+	defer span.finish()
+	span.setAttributes(stringAttribute("steamidtools.conversion", label), intAttribute("steamidtools.batch.items", len(steamids)))
+
+	items := make([]targetItem, 0, len(steamids))
+	for _, id := range steamids {
+		if id == "" {
+			continue
+		}
+		items = append(items, convertToTargets(ctx, id, lang, opts, targets))
+	}
+	metrics.observeBatchSize(metricsEndpointLabel(r.URL.Path), len(steamids))
+	failed := 0
+	for _, item := range items {
+		for _, result := range item.Results {
+			if !result.Error.IsValid() {
+				metrics.countError(result.Error, errorScopeItem)
+				failed++
+			}
+		}
+	}
+	span.setAttributes(intAttribute("steamidtools.batch.failed", failed))
+
+	detected := targets[0].Config.detectsSource()
+	switch {
+	case wantsKeyValueBinary(r):
+		writeKeyValueBinaryResponse(w, r, http.StatusOK, formatTargetsAsBinaryKeyValue(items, "SteamIDTools", targets, detected))
+	case wantsJSON(r):
+		writeJSONResponse(w, r, http.StatusOK, localizeTargetItems(items, targets, detected, lang))
+	default:
+		writeKeyValueResponse(w, formatTargetsAsKeyValue(items, "SteamIDTools", targets, detected, lang), hasNullTerm(r))
+	}
+	appInfof("multi-target conversion processed: conversion=%s items=%d remote_addr=%s", label, len(steamids), r.RemoteAddr)
+}
+
+func convertToTargets(ctx context.Context, input, lang string, opts conversionOptions, targets []conversionTarget) targetItem {
+	item := targetItem{Input: input, Results: make([]BatchItemResult, 0, len(targets))}
+	for _, target := range targets {
+		format, result := target.Config.convertInput(ctx, input, lang, opts)
+		item.Format = format
+		item.Results = append(item.Results, BatchItemResult{Value: result.Value, Error: result.Error})
+	}
+	return item
+}
+
+func formatTargetsAsKeyValue(items []targetItem, sectionName string, targets []conversionTarget, detected bool, lang string) string {
+	section := keyvalues.NewSection(sectionName)
+	for _, item := range items {
+		entry := section.AddSection(item.Input)
+		if detected {
+			entry.AddValue("format", detectedFormatName(item.Format))
+		}
+		for i, result := range item.Results {
+			value := result.Value
+			if !result.Error.IsValid() {
+				value = "ERROR: " + localizedErrorMessage(result.Error, lang)
+			}
+			entry.AddValue(string(targets[i].Format), value)
+		}
+	}
+
+	return string(keyvalues.Marshal(section))
+}
+
+func formatTargetsAsBinaryKeyValue(items []targetItem, sectionName string, targets []conversionTarget, detected bool) keyvalues.Node {
+	section := keyvalues.NewSection(sectionName)
+	for _, item := range items {
+		entry := section.AddSection(item.Input)
+		if detected {
+			entry.AddValue("format", detectedFormatName(item.Format))
+		}
+		for i, result := range item.Results {
+			entry.Children = append(entry.Children, binaryResultSection(string(targets[i].Format), result))
+		}
+	}
+
+	return section
+}
+
+func localizeTargetItems(items []targetItem, targets []conversionTarget, detected bool, lang string) []TargetItemResult {
+	localized := make([]TargetItemResult, 0, len(items))
+	for _, item := range items {
+		result := TargetItemResult{Input: item.Input, Targets: make(map[steamIDFormat]TargetValue, len(targets))}
+		if detected {
+			result.Format = item.Format
+		}
+		for i, value := range item.Results {
+			value = localizeBatchItem(value, lang)
+			result.Targets[targets[i].Format] = TargetValue{Value: value.Value, Error: value.Error, Message: value.Message}
+		}
+		localized = append(localized, result)
+	}
+
+	return localized
+}
@@ -0,0 +1,106 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandleConvertMultipleTargets(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		method     string
+		query      string
+		body       string
+		accept     string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "keyvalue section per input",
+			method:     http.MethodGet,
+			query:      "from=sid64&targets=sid2,sid3,sid2&universe=0&steamid=76561197960287930,123",
+			wantStatus: http.StatusOK,
+			wantBody: "\"SteamIDTools\"\n{\n" +
+				"    \"76561197960287930\"\n    {\n" +
+				"        \"sid2\" \"STEAM_0:0:11101\"\n" +
+				"        \"sid3\" \"[U:1:22202]\"\n" +
+				"    }\n" +
+				"    \"123\"\n    {\n" +
+				"        \"sid2\" \"ERROR: SteamID length is incorrect\"\n" +
+				"        \"sid3\" \"ERROR: SteamID length is incorrect\"\n" +
+				"    }\n" +
+				"}",
+		},
+		{
+			name:       "single input still gets a section",
+			method:     http.MethodGet,
+			query:      "from=auto&targets=AID,url&steamid=STEAM_1:0:11101",
+			wantStatus: http.StatusOK,
+			wantBody: "\"SteamIDTools\"\n{\n" +
+				"    \"STEAM_1:0:11101\"\n    {\n" +
+				"        \"format\" \"sid2\"\n" +
+				"        \"aid\" \"22202\"\n" +
+				"        \"url\" \"https://steamcommunity.com/profiles/76561197960287930\"\n" +
+				"    }\n" +
+				"}",
+		},
+		{
+			name:       "json objects keyed by target",
+			method:     http.MethodPost,
+			query:      "from=auto&targets=sid64,aid",
+			body:       "[U:1:22202]\nbogus",
+			accept:     "application/json",
+			wantStatus: http.StatusOK,
+			wantBody: `[{"input":"[U:1:22202]","format":"sid3","targets":{"aid":{"value":"22202"},"sid64":{"value":"76561197960287930"}}},` +
+				`{"input":"bogus","targets":{"aid":{"error":"invalid_format","message":"Invalid SteamID format provided"},"sid64":{"error":"invalid_format","message":"Invalid SteamID format provided"}}}]`,
+		},
+		{
+			name:       "empty targets",
+			method:     http.MethodGet,
+			query:      "from=sid64&targets=,&steamid=76561197960287930",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "targets must list at least one format",
+		},
+		{
+			name:       "target outside the source family",
+			method:     http.MethodGet,
+			query:      "from=sid64&targets=sid2,gid3&steamid=76561197960287930",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "conversion from sid64 to gid3 is not supported (sid64 converts to: sid64, aid, sid2, sid3, hex, url)",
+		},
+		{
+			name:       "steamid still required",
+			method:     http.MethodGet,
+			query:      "from=sid64&targets=sid2",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "steamid parameter required",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(tc.method, EndpointConvert+"?"+tc.query, strings.NewReader(tc.body))
+			req.Header.Set("Accept-Language", "en")
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+			rec := httptest.NewRecorder()
+
+			HandleConvert(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Fatalf("expected status %d, got %d (%s)", tc.wantStatus, rec.Code, rec.Body.String())
+			}
+			if body := rec.Body.String(); body != tc.wantBody {
+				t.Fatalf("unexpected body:\n%s", body)
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// traceQueueSize bounds finished spans waiting for export; more are dropped
+	// so a slow collector never blocks requests.
+	traceQueueSize     = 4096
+	traceBatchSize     = 512
+	traceFlushInterval = 5 * time.Second
+	traceExportTimeout = 10 * time.Second
+)
+
+// spanExporter sends finished spans to a collector.
+type spanExporter interface {
+	ExportSpans(spans []*span) error
+}
+
+// tracer batches finished spans and hands them to its exporter in the background.
+type tracer struct {
+	exporter spanExporter
+	queue    chan *span
+	flushes  chan chan struct{}
+	dropped  atomic.Int64
+}
+
+func newTracer(exporter spanExporter) *tracer {
+	t := &tracer{
+		exporter: exporter,
+		queue:    make(chan *span, traceQueueSize),
+		flushes:  make(chan chan struct{}),
+	}
+	go t.run()
+	return t
+}
+
+func (t *tracer) enqueue(s *span) {
+	select {
+	case t.queue <- s:
+	default:
+		t.dropped.Add(1)
+	}
+}
+
+// flush exports every span finished so far and waits for the export.
+func (t *tracer) flush() {
+	done := make(chan struct{})
+	t.flushes <- done
+	<-done
+}
+
+// flushContext is flush bounded by ctx, for shutdown.
+func (t *tracer) flushContext(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		t.flush()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+}
+
+func (t *tracer) run() {
+	ticker := time.NewTicker(traceFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*span, 0, traceBatchSize)
+	for {
+		select {
+		case s := <-t.queue:
+			batch = append(batch, s)
+			if len(batch) >= traceBatchSize {
+				batch = t.export(batch)
+			}
+		case <-ticker.C:
+			batch = t.export(batch)
+		case done := <-t.flushes:
+			for drained := false; !drained; {
+				select {
+				case s := <-t.queue:
+					batch = append(batch, s)
+				default:
+					drained = true
+				}
+			}
+			batch = t.export(batch)
+			close(done)
+		}
+	}
+}
+
+func (t *tracer) export(batch []*span) []*span {
+	if dropped := t.dropped.Swap(0); dropped > 0 {
+		appWarnf("trace spans dropped: count=%d", dropped)
+	}
+	if len(batch) == 0 {
+		return batch
+	}
+	if err := t.exporter.ExportSpans(batch); err != nil {
+		appWarnf("trace export failed: spans=%d error=%s", len(batch), err.Error())
+	}
+	return batch[:0]
+}
+
+// otlpExporter posts spans as OTLP/HTTP JSON, the encoding every OpenTelemetry
+// collector accepts on its /v1/traces endpoint.
+type otlpExporter struct {
+	url         string
+	serviceName string
+	client      *http.Client
+}
+
+func newOTLPExporter(url, serviceName string) *otlpExporter {
+	return &otlpExporter{
+		url:         url,
+		serviceName: serviceName,
+		client:      &http.Client{Timeout: traceExportTimeout},
+	}
+}
+
+func (e *otlpExporter) ExportSpans(spans []*span) error {
+	body, err := json.Marshal(newOTLPTraceRequest(e.serviceName, spans))
+	if err != nil {
+		return err
+	}
+
+	resp, err := e.client.Post(e.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("collector answered %s", resp.Status)
+	}
+	return nil
+}
+
+// OTLP JSON mirrors the protobuf messages; IDs are hex and 64-bit integers strings.
+type otlpTraceRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              spanKind       `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Events            []otlpEvent    `json:"events,omitempty"`
+	DroppedEvents     int            `json:"droppedEventsCount,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpEvent struct {
+	TimeUnixNano string         `json:"timeUnixNano"`
+	Name         string         `json:"name"`
+	Attributes   []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+// otlpStatusError is STATUS_CODE_ERROR; unset status is left out.
+const otlpStatusError = 2
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string `json:"stringValue,omitempty"`
+	IntValue    string  `json:"intValue,omitempty"`
+	BoolValue   *bool   `json:"boolValue,omitempty"`
+}
+
+func stringAttribute(key, value string) otlpKeyValue {
+	return otlpKeyValue{Key: key, Value: otlpAnyValue{StringValue: &value}}
+}
+
+func intAttribute(key string, value int) otlpKeyValue {
+	return otlpKeyValue{Key: key, Value: otlpAnyValue{IntValue: strconv.Itoa(value)}}
+}
+
+func boolAttribute(key string, value bool) otlpKeyValue {
+	return otlpKeyValue{Key: key, Value: otlpAnyValue{BoolValue: &value}}
+}
+
+func newOTLPTraceRequest(serviceName string, spans []*span) otlpTraceRequest {
+	encoded := make([]otlpSpan, 0, len(spans))
+	for _, s := range spans {
+		item := otlpSpan{
+			TraceID:           hex.EncodeToString(s.context.TraceID[:]),
+			SpanID:            hex.EncodeToString(s.context.SpanID[:]),
+			Name:              s.name,
+			Kind:              s.kind,
+			StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.end.UnixNano(), 10),
+			Attributes:        s.attributes,
+			DroppedEvents:     s.dropped,
+		}
+		for _, event := range s.events {
+			item.Events = append(item.Events, otlpEvent{
+				TimeUnixNano: strconv.FormatInt(event.time.UnixNano(), 10),
+				Name:         event.name,
+				Attributes:   event.attributes,
+			})
+		}
+		if s.parent != [8]byte{} {
+			item.ParentSpanID = hex.EncodeToString(s.parent[:])
+		}
+		if s.errorKey != "" {
+			item.Status = otlpStatus{Code: otlpStatusError, Message: s.errorKey}
+		}
+		encoded = append(encoded, item)
+	}
+
+	return otlpTraceRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource: otlpResource{Attributes: []otlpKeyValue{
+			stringAttribute("service.name", serviceName),
+			stringAttribute("service.version", Version),
+		}},
+		ScopeSpans: []otlpScopeSpans{{
+			Scope: otlpScope{Name: "steamid-service", Version: Version},
+			Spans: encoded,
+		}},
+	}}}
+}
@@ -0,0 +1,224 @@
+package app
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Tracing follows OpenTelemetry: W3C trace context in, OTLP/HTTP JSON out. Like
+// the metrics it is written with the standard library to keep go.mod small.
+
+const traceparentHeader = "traceparent"
+
+type spanKind int
+
+// Span kinds use the OTLP enum values.
+const (
+	spanKindInternal spanKind = 1
+	spanKindServer   spanKind = 2
+)
+
+// maxSpanEvents caps the events kept per span; a batch with thousands of bad
+// items records the first ones and counts the rest as dropped.
+const maxSpanEvents = 32
+
+type spanContext struct {
+	TraceID [16]byte
+	SpanID  [8]byte
+	Sampled bool
+}
+
+// parseTraceparent reads a W3C traceparent header (version 00 layout). All-zero
+// IDs are invalid, as the spec requires.
+func parseTraceparent(value string) (spanContext, bool) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return spanContext{}, false
+	}
+
+	var sc spanContext
+	if !decodeTraceHex(sc.TraceID[:], parts[1]) || !decodeTraceHex(sc.SpanID[:], parts[2]) {
+		return spanContext{}, false
+	}
+	var flags [1]byte
+	if !decodeTraceHex(flags[:], parts[3]) {
+		return spanContext{}, false
+	}
+	if sc.TraceID == [16]byte{} || sc.SpanID == [8]byte{} {
+		return spanContext{}, false
+	}
+	sc.Sampled = flags[0]&1 == 1
+	return sc, true
+}
+
+// decodeTraceHex fills dst from lowercase hex of exactly the right length.
+func decodeTraceHex(dst []byte, value string) bool {
+	if len(value) != 2*len(dst) || strings.ToLower(value) != value {
+		return false
+	}
+	_, err := hex.Decode(dst, []byte(value))
+	return err == nil
+}
+
+func (sc spanContext) traceparent() string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	return "00-" + hex.EncodeToString(sc.TraceID[:]) + "-" + hex.EncodeToString(sc.SpanID[:]) + "-" + flags
+}
+
+// span is one timed operation. A nil span is valid and records nothing, so
+// callers never check whether tracing is enabled.
+type span struct {
+	tracer     *tracer
+	context    spanContext
+	parent     [8]byte
+	name       string
+	kind       spanKind
+	start      time.Time
+	end        time.Time
+	attributes []otlpKeyValue
+	events     []spanEvent
+	dropped    int
+	errorKey   string
+	// items marks a span covering many conversions, which record failures as
+	// events on it instead of starting spans of their own.
+	items bool
+}
+
+// spanEvent is a point in time inside a span, such as one failed batch item.
+type spanEvent struct {
+	name       string
+	time       time.Time
+	attributes []otlpKeyValue
+}
+
+type spanContextKey struct{}
+
+func spanFromContext(ctx context.Context) *span {
+	s, _ := ctx.Value(spanContextKey{}).(*span)
+	return s
+}
+
+func (s *span) recording() bool {
+	return s != nil && s.tracer != nil
+}
+
+func (s *span) aggregatesItems() bool {
+	return s.recording() && s.items
+}
+
+func (s *span) setAttributes(attributes ...otlpKeyValue) {
+	if s.recording() {
+		s.attributes = append(s.attributes, attributes...)
+	}
+}
+
+// addEvent records an event until the span holds maxSpanEvents of them.
+func (s *span) addEvent(name string, attributes ...otlpKeyValue) {
+	if !s.recording() {
+		return
+	}
+	if len(s.events) >= maxSpanEvents {
+		s.dropped++
+		return
+	}
+	s.events = append(s.events, spanEvent{name: name, time: time.Now(), attributes: attributes})
+}
+
+// setError marks the span failed with a SteamIDError key.
+func (s *span) setError(err SteamIDError) {
+	if s.recording() && !err.IsValid() {
+		s.errorKey = err.Key()
+	}
+}
+
+func (s *span) finish() {
+	if s.recording() {
+		s.end = time.Now()
+		s.tracer.enqueue(s)
+	}
+}
+
+// traceID is the hex trace ID for logs, or "" when there is no trace.
+func (s *span) traceID() string {
+	if s == nil {
+		return ""
+	}
+	return hex.EncodeToString(s.context.TraceID[:])
+}
+
+var activeTracer atomic.Pointer[tracer]
+
+// startSpan starts a child of the span in ctx, or a new trace. It returns a nil
+// span when tracing is off or the parent was not sampled.
+func startSpan(ctx context.Context, name string) (context.Context, *span) {
+	return startSpanKind(ctx, name, spanKindInternal)
+}
+
+// startItemsSpan starts a span for a batch, stream or CSV conversion; the items
+// converted under it do not get spans of their own.
+func startItemsSpan(ctx context.Context, name string) (context.Context, *span) {
+	ctx, s := startSpan(ctx, name)
+	if s != nil {
+		s.items = true
+	}
+	return ctx, s
+}
+
+func startSpanKind(ctx context.Context, name string, kind spanKind) (context.Context, *span) {
+	t := activeTracer.Load()
+	parent := spanFromContext(ctx)
+	if t == nil || (parent != nil && !parent.context.Sampled) {
+		return ctx, nil
+	}
+
+	s := &span{tracer: t, name: name, kind: kind, start: time.Now()}
+	s.context.Sampled = true
+	if parent != nil {
+		s.context.TraceID = parent.context.TraceID
+		s.parent = parent.context.SpanID
+	} else {
+		_, _ = rand.Read(s.context.TraceID[:])
+	}
+	_, _ = rand.Read(s.context.SpanID[:])
+	return context.WithValue(ctx, spanContextKey{}, s), s
+}
+
+// tracingMiddleware starts the server span of each request, continuing the
+// caller's trace when it sends a valid traceparent.
+func tracingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if remote, ok := parseTraceparent(r.Header.Get(traceparentHeader)); ok {
+			ctx = context.WithValue(ctx, spanContextKey{}, &span{context: remote})
+		}
+
+		route, method := metricsEndpointLabel(r.URL.Path), metricsMethodLabel(r.Method)
+		ctx, s := startSpanKind(ctx, method+" "+route, spanKindServer)
+		recorder := &responseRecorder{
+			ResponseWriter: w,
+			status:         http.StatusOK,
+		}
+
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+
+		s.setAttributes(
+			stringAttribute("http.request.method", method),
+			stringAttribute("http.route", route),
+			stringAttribute("client.address", r.RemoteAddr),
+			intAttribute("http.response.status_code", recorder.status),
+		)
+		if recorder.status >= http.StatusInternalServerError && s.recording() && s.errorKey == "" {
+			s.errorKey = strconv.Itoa(recorder.status)
+		}
+		s.finish()
+	})
+}
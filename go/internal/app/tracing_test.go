@@ -0,0 +1,176 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		value       string
+		wantOK      bool
+		wantSampled bool
+	}{
+		{name: "sampled", value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", wantOK: true, wantSampled: true},
+		{name: "not sampled", value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", wantOK: true},
+		{name: "future version with extra fields", value: "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-x", wantOK: true, wantSampled: true},
+		{name: "version 00 with extra fields", value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-x"},
+		{name: "invalid version", value: "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		{name: "uppercase", value: "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01"},
+		{name: "zero trace id", value: "00-00000000000000000000000000000000-00f067aa0ba902b7-01"},
+		{name: "zero span id", value: "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01"},
+		{name: "short span id", value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902-01"},
+		{name: "empty", value: ""},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			sc, ok := parseTraceparent(tc.value)
+			if ok != tc.wantOK || sc.Sampled != tc.wantSampled {
+				t.Fatalf("expected ok=%v sampled=%v, got ok=%v sampled=%v", tc.wantOK, tc.wantSampled, ok, sc.Sampled)
+			}
+			if ok && tc.value[:2] == "00" && sc.traceparent() != tc.value {
+				t.Fatalf("expected %q to round trip, got %q", tc.value, sc.traceparent())
+			}
+		})
+	}
+}
+
+// serveTraced installs a tracer exporting to a test collector, serves target
+// through the tracing middleware and returns the spans the collector received.
+// Callers must not run in parallel: the tracer is process-wide.
+func serveTraced(t *testing.T, target, traceparent string) []otlpSpan {
+	t.Helper()
+
+	var mu sync.Mutex
+	var spans []otlpSpan
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request otlpTraceRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("collector got invalid OTLP JSON: %v", err)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for _, resource := range request.ResourceSpans {
+			for _, scope := range resource.ScopeSpans {
+				spans = append(spans, scope.Spans...)
+			}
+		}
+	}))
+	defer collector.Close()
+
+	tr := newTracer(newOTLPExporter(collector.URL+"/v1/traces", "steamid-service"))
+	activeTracer.Store(tr)
+	defer activeTracer.Store(nil)
+
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req.Header.Set(traceparentHeader, traceparent)
+	tracingMiddleware(newHandlerMux(false)).ServeHTTP(httptest.NewRecorder(), req)
+	tr.flush()
+
+	mu.Lock()
+	defer mu.Unlock()
+	return spans
+}
+
+// Not parallel: it installs the process-wide tracer.
+func TestTracingExportsConversionSpans(t *testing.T) {
+	target := EndpointSID64toAID + "?steamid=76561197960287930,nope"
+	if spans := serveTraced(t, target, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00"); len(spans) != 0 {
+		t.Fatalf("expected no spans for an unsampled parent, got %d", len(spans))
+	}
+
+	spans := serveTraced(t, target, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	byName := make(map[string][]otlpSpan)
+	for _, s := range spans {
+		if s.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+			t.Fatalf("span %s left the caller's trace: %s", s.Name, s.TraceID)
+		}
+		byName[s.Name] = append(byName[s.Name], s)
+	}
+
+	server, conversion, batch := byName["GET /SID64toAID"], byName["handleConversion"], byName["batch"]
+	if len(spans) != 3 || len(server) != 1 || len(conversion) != 1 || len(batch) != 1 {
+		t.Fatalf("unexpected spans %+v", byName)
+	}
+	if server[0].ParentSpanID != "00f067aa0ba902b7" || server[0].Kind != spanKindServer ||
+		conversion[0].ParentSpanID != server[0].SpanID || batch[0].ParentSpanID != conversion[0].SpanID {
+		t.Fatalf("unexpected span tree %+v", byName)
+	}
+
+	events := batch[0].Events
+	if len(events) != 1 || events[0].Name != "steamidtools.conversion_failed" || batch[0].Status.Code == otlpStatusError {
+		t.Fatalf("expected one failed item event on a successful batch span, got %+v", batch[0])
+	}
+	for _, s := range spans {
+		for _, attribute := range append(s.Attributes, events[0].Attributes...) {
+			if value := attribute.Value.StringValue; value != nil && strings.Contains(*value, "nope") {
+				t.Fatalf("span %s copied user input into %s", s.Name, attribute.Key)
+			}
+		}
+	}
+	attributes := make(map[string]otlpAnyValue)
+	for _, attribute := range append(batch[0].Attributes, events[0].Attributes...) {
+		attributes[attribute.Key] = attribute.Value
+	}
+	if attributes["steamidtools.batch.items"].IntValue != "2" || attributes["steamidtools.batch.failed"].IntValue != "1" ||
+		attributes["steamidtools.error"].StringValue == nil || *attributes["steamidtools.error"].StringValue != "invalid_length" {
+		t.Fatalf("unexpected batch attributes %+v", attributes)
+	}
+}
+
+// Not parallel: it installs the process-wide tracer.
+func TestTracingExportsStepSpansForSingleConversion(t *testing.T) {
+	spans := serveTraced(t, EndpointSID64toAID+"?steamid=76561197960287930", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	byName := make(map[string][]otlpSpan)
+	for _, s := range spans {
+		byName[s.Name] = append(byName[s.Name], s)
+	}
+
+	conversion, run, steps := byName["handleConversion"], byName["runConversionSteps"], byName["conversionStep"]
+	if len(conversion) != 1 || len(run) != 1 || len(steps) != 2 || run[0].ParentSpanID != conversion[0].SpanID {
+		t.Fatalf("unexpected spans %+v", byName)
+	}
+
+	var got []string
+	for _, s := range steps {
+		if s.ParentSpanID != run[0].SpanID || s.Status.Code == otlpStatusError {
+			t.Fatalf("unexpected step span %+v", s)
+		}
+		attributes := make(map[string]string)
+		for _, attribute := range s.Attributes {
+			if attribute.Value.StringValue != nil {
+				attributes[attribute.Key] = *attribute.Value.StringValue
+			}
+		}
+		got = append(got, attributes["steamidtools.step.from"]+"->"+attributes["steamidtools.step.to"])
+	}
+	slices.Sort(got)
+	if !slices.Equal(got, []string{"sid64->aid", "sid64->sid64"}) {
+		t.Fatalf("unexpected step formats %v", got)
+	}
+}
+
+func TestSpanEventsAreCapped(t *testing.T) {
+	t.Parallel()
+
+	s := &span{tracer: &tracer{}}
+	for i := 0; i < maxSpanEvents+5; i++ {
+		s.addEvent("steamidtools.conversion_failed", intAttribute("steamidtools.step", 0))
+	}
+	encoded := newOTLPTraceRequest("steamid-service", []*span{s}).ResourceSpans[0].ScopeSpans[0].Spans[0]
+	if len(encoded.Events) != maxSpanEvents || encoded.DroppedEvents != 5 {
+		t.Fatalf("expected %d events and 5 dropped, got %d and %d", maxSpanEvents, len(encoded.Events), encoded.DroppedEvents)
+	}
+}
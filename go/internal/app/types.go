@@ -1,30 +1,85 @@
 package app
 
+import "steamid-service/pkg/steamid"
+
 type SteamIDError string
 
+// Errors produced by parsing or formatting share their keys with pkg/steamid.
 const (
 	ErrorNone               SteamIDError = "none"
-	ErrorInvalidFormat      SteamIDError = "invalid_format"
-	ErrorInvalidLength      SteamIDError = "invalid_length"
-	ErrorInvalidCharacters  SteamIDError = "invalid_characters"
-	ErrorInvalidSteamID2    SteamIDError = "invalid_steamid2"
-	ErrorInvalidSteamID3    SteamIDError = "invalid_steamid3"
-	ErrorInvalidSteamID64   SteamIDError = "invalid_steamid64"
-	ErrorInvalidAccountID   SteamIDError = "invalid_accountid"
+	ErrorInvalidFormat      SteamIDError = SteamIDError(steamid.ErrInvalidFormat)
+	ErrorInvalidLength      SteamIDError = SteamIDError(steamid.ErrInvalidLength)
+	ErrorInvalidCharacters  SteamIDError = SteamIDError(steamid.ErrInvalidCharacters)
+	ErrorInvalidSteamID2    SteamIDError = SteamIDError(steamid.ErrInvalidSteamID2)
+	ErrorInvalidSteamID3    SteamIDError = SteamIDError(steamid.ErrInvalidSteamID3)
+	ErrorInvalidSteamID64   SteamIDError = SteamIDError(steamid.ErrInvalidSteamID64)
+	ErrorInvalidAccountID   SteamIDError = SteamIDError(steamid.ErrInvalidAccountID)
 	ErrorConversionFailed   SteamIDError = "conversion_failed"
 	ErrorMissingParameter   SteamIDError = "missing_parameter"
 	ErrorServiceUnavailable SteamIDError = "service_unavailable"
 	ErrorDuplicateInBatch   SteamIDError = "duplicate_in_batch"
+
+	ErrorUnsupportedAccountType SteamIDError = SteamIDError(steamid.ErrUnsupportedAccountType)
+	ErrorInvalidGroupID         SteamIDError = SteamIDError(steamid.ErrInvalidGroupID)
+	ErrorInvalidUniverse        SteamIDError = SteamIDError(steamid.ErrInvalidUniverse)
+	ErrorUnsupportedConversion  SteamIDError = "unsupported_conversion"
+	ErrorBodyTooLarge           SteamIDError = "body_too_large"
+	ErrorJobNotFound            SteamIDError = "job_not_found"
+	ErrorInvalidEndpoint        SteamIDError = "invalid_endpoint"
+	ErrorJobInterrupted         SteamIDError = "job_interrupted"
 )
 
 func (e SteamIDError) Error() string { return string(e) }
 func (e SteamIDError) Key() string   { return string(e) }
 
+// errorCodes are the stable numeric codes written by binary KeyValues responses.
+// They mirror the SteamIDToolsError enum in the SourceMod include; append only.
+var errorCodes = map[SteamIDError]int32{
+	ErrorNone:                   0,
+	ErrorInvalidFormat:          1,
+	ErrorInvalidLength:          2,
+	ErrorInvalidCharacters:      3,
+	ErrorInvalidSteamID2:        4,
+	ErrorInvalidSteamID3:        5,
+	ErrorInvalidSteamID64:       6,
+	ErrorInvalidAccountID:       7,
+	ErrorConversionFailed:       8,
+	ErrorMissingParameter:       9,
+	ErrorServiceUnavailable:     10,
+	ErrorDuplicateInBatch:       11,
+	ErrorUnsupportedAccountType: 12,
+	ErrorInvalidGroupID:         13,
+	ErrorInvalidUniverse:        14,
+	ErrorUnsupportedConversion:  15,
+	ErrorBodyTooLarge:           16,
+	ErrorJobNotFound:            17,
+	ErrorInvalidEndpoint:        18,
+	ErrorJobInterrupted:         19,
+}
+
+// Code returns the numeric code of e; unknown errors report conversion_failed.
+func (e SteamIDError) Code() int32 {
+	if code, ok := errorCodes[e]; ok {
+		return code
+	}
+	return errorCodes[ErrorConversionFailed]
+}
+
 const (
-	STEAMID64_BASE = uint64(76561197960265728)
-	MaxAccountID   = uint64((1 << 32) - 1)
+	STEAMID64_BASE = steamid.BaseSteamID64
+	MaxAccountID   = steamid.MaxAccountID
 	MaxSteamID64   = STEAMID64_BASE + MaxAccountID
 	SID2_UNIVERSE  = "1"
+
+	SID2UniverseQueryParam = "universe"
+	SID2UniverseHeader     = "X-SteamIDTools-SID2-Universe"
+	JobStatusHeader        = "X-SteamIDTools-Job-Status"
+	ErrorHeader            = "X-SteamIDTools-Error"
+
+	SteamCommunityHost    = steamid.SteamCommunityHost
+	SteamIDHexPrefix      = steamid.HexPrefix
+	ProfileURLPrefix      = steamid.ProfileURLPrefix
+	GroupProfileURLPrefix = steamid.GroupProfileURLPrefix
 )
 
 const (
@@ -35,6 +90,21 @@ const (
 	EndpointSID2toSID64 = "/SID2toSID64"
 	EndpointSID3toSID64 = "/SID3toSID64"
 	EndpointHealth      = "/health"
+
+	EndpointGID64toClanID = "/GID64toClanID"
+	EndpointGID64toGID3   = "/GID64toGID3"
+	EndpointClanIDtoGID64 = "/ClanIDtoGID64"
+	EndpointClanIDtoGID3  = "/ClanIDtoGID3"
+	EndpointGID3toGID64   = "/GID3toGID64"
+	EndpointGID3toClanID  = "/GID3toClanID"
+
+	EndpointDescribe = "/describe"
+	EndpointConvert  = "/convert"
+	EndpointStream   = "/stream"
+	EndpointCSV      = "/csv"
+	EndpointJobs     = "/jobs"
+	EndpointErrors   = "/errors"
+	EndpointMetrics  = "/metrics"
 )
 
 type ConversionResult struct {
@@ -42,12 +112,98 @@ type ConversionResult struct {
 	Error SteamIDError
 }
 
+// BatchItemResult is one converted batch entry. Error holds the machine-readable
+// code and Message its localized text; both are empty for successful items in JSON.
+// Account and Aliases are only set with identity=account.
 type BatchItemResult struct {
-	Input string
-	Value string
-	Error SteamIDError
+	Input   string        `json:"input"`
+	Format  steamIDFormat `json:"format,omitempty"`
+	Value   string        `json:"value,omitempty"`
+	Error   SteamIDError  `json:"error,omitempty"`
+	Message string        `json:"message,omitempty"`
+	Account string        `json:"account,omitempty"`
+	Aliases []string      `json:"aliases,omitempty"`
 }
 
+// BatchResult holds converted items in input order. Positional batches key their
+// KeyValue entries by index, Identity marks items grouped by account, and Summary
+// is set when the request named a mode. Paged results hold the items from Offset
+// on and carry a Cursor while more pages remain.
 type BatchResult struct {
-	Items []BatchItemResult
+	Items      []BatchItemResult
+	Positional bool
+	Identity   bool
+	Summary    *BatchSummary
+	Paged      bool
+	Offset     int
+	Cursor     string
+}
+
+// BatchSummary counts the items of a batch; Duplicates is only non-zero in dedupe mode.
+type BatchSummary struct {
+	Total      int `json:"total"`
+	Succeeded  int `json:"succeeded"`
+	Failed     int `json:"failed"`
+	Duplicates int `json:"duplicates"`
+}
+
+// BatchResponse is the JSON body of a batch answered with an explicit mode or
+// paginated; Cursor is empty on the last page.
+type BatchResponse struct {
+	Items   []BatchItemResult `json:"items"`
+	Summary *BatchSummary     `json:"summary,omitempty"`
+	Cursor  string            `json:"cursor,omitempty"`
+}
+
+// TargetItemResult is one input of a multi-target conversion, keyed by target format.
+type TargetItemResult struct {
+	Input   string                        `json:"input"`
+	Format  steamIDFormat                 `json:"format,omitempty"`
+	Targets map[steamIDFormat]TargetValue `json:"targets"`
+}
+
+// TargetValue is the value or the error of one target; see BatchItemResult.
+type TargetValue struct {
+	Value   string       `json:"value,omitempty"`
+	Error   SteamIDError `json:"error,omitempty"`
+	Message string       `json:"message,omitempty"`
+}
+
+// ConversionResponse is the JSON body of a single conversion.
+type ConversionResponse struct {
+	Input  string        `json:"input"`
+	Format steamIDFormat `json:"format,omitempty"`
+	Value  string        `json:"value"`
+}
+
+// JobResponse is the JSON body of a job that has not finished yet.
+type JobResponse struct {
+	Job    string    `json:"job"`
+	Status jobStatus `json:"status"`
+}
+
+// ProblemResponse is the RFC 9457 body of a failed JSON request. Error and
+// Message repeat the key and detail under the names earlier clients read; Input
+// and Index name the entry that failed, when there is one.
+type ProblemResponse struct {
+	Type    string `json:"type"`
+	Title   string `json:"title"`
+	Status  int    `json:"status"`
+	Detail  string `json:"detail"`
+	Error   string `json:"error"`
+	Message string `json:"message"`
+	Input   string `json:"input,omitempty"`
+	Index   *int   `json:"index,omitempty"`
+}
+
+// ErrorResponse is the error line of an NDJSON stream.
+type ErrorResponse struct {
+	Error   string `json:"error"`
+	Message string `json:"message"`
+}
+
+// HealthResponse is the JSON body of the health check.
+type HealthResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
 }
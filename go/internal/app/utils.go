@@ -1,11 +1,13 @@
 package app
 
 import (
-	"fmt"
+	"encoding/json"
 	"io"
 	"net/http"
 	"strconv"
 	"strings"
+
+	"steamid-service/pkg/keyvalues"
 )
 
 var errorMessages = map[SteamIDError]string{
@@ -21,6 +23,15 @@ var errorMessages = map[SteamIDError]string{
 	ErrorMissingParameter:   "Missing required parameter",
 	ErrorServiceUnavailable: "SteamID conversion service is unavailable",
 	ErrorDuplicateInBatch:   "Duplicate SteamID found in batch",
+
+	ErrorUnsupportedAccountType: "Account type cannot be represented in the requested format",
+	ErrorInvalidGroupID:         "Invalid Steam group ID (expected a group SteamID64, [g:1:N] or clan AccountID)",
+	ErrorInvalidUniverse:        "Invalid SteamID2 universe (expected 0 or 1)",
+	ErrorUnsupportedConversion:  "Unsupported conversion pair",
+	ErrorBodyTooLarge:           "Request body exceeds the configured size limit",
+	ErrorJobNotFound:            "Job not found or expired",
+	ErrorInvalidEndpoint:        "Invalid endpoint",
+	ErrorJobInterrupted:         "Job interrupted by a service restart; submit the batch again",
 }
 
 func (e SteamIDError) IsValid() bool {
@@ -49,114 +60,329 @@ func writePlainTextBody(w io.Writer, body string) {
 	_, _ = io.WriteString(w, body)
 }
 
-func appendKeyValueLine(builder *strings.Builder, input, value string) {
-	// #nosec G705 -- batch responses are emitted as Valve KeyValue text, not HTML.
-	_, _ = fmt.Fprintf(builder, "    \"%s\" \"%s\"\n", input, value)
-}
-
 func formatAsKeyValue(results BatchResult, sectionName string, lang string) string {
-	var builder strings.Builder
-	_, _ = fmt.Fprintf(&builder, "\"%s\"\n{\n", sectionName)
-
-	for _, item := range results.Items {
+	section := keyvalues.NewSection(sectionName)
+	for i, item := range results.Items {
 		if item.Error.IsValid() {
-			appendKeyValueLine(&builder, item.Input, item.Value)
+			section.AddValue(results.itemKey(i), item.Value)
 			continue
 		}
 
-		appendKeyValueLine(&builder, item.Input, "ERROR: "+localizedErrorMessage(item.Error, lang))
+		section.AddValue(results.itemKey(i), "ERROR: "+localizedErrorMessage(item.Error, lang))
+	}
+	results.appendTrailer(&section)
+
+	return string(keyvalues.Marshal(section))
+}
+
+// formatAsSectionedKeyValue writes one section per item, used when items carry
+// more than a value: the detected format with from=auto, and the account and
+// merged aliases with identity=account.
+func formatAsSectionedKeyValue(results BatchResult, sectionName string, detected bool, lang string) string {
+	section := keyvalues.NewSection(sectionName)
+	for i, item := range results.Items {
+		value := item.Value
+		if !item.Error.IsValid() {
+			value = "ERROR: " + localizedErrorMessage(item.Error, lang)
+		}
+
+		entry := section.AddSection(results.itemKey(i))
+		if detected {
+			entry.AddValue("format", detectedFormatName(item.Format))
+		}
+		entry.AddValue("value", value)
+		appendIdentity(entry, item)
+	}
+	results.appendTrailer(&section)
+
+	return string(keyvalues.Marshal(section))
+}
+
+// appendIdentity adds the canonical account and the merged inputs of item.
+func appendIdentity(entry *keyvalues.Node, item BatchItemResult) {
+	if item.Account != "" {
+		entry.AddValue("account", item.Account)
+	}
+	if len(item.Aliases) == 0 {
+		return
+	}
+
+	aliases := entry.AddSection("aliases")
+	for i, alias := range item.Aliases {
+		aliases.AddValue(strconv.Itoa(i), alias)
+	}
+}
+
+// formatAsBinaryKeyValue builds the format=kvbinary batch document: one section
+// per input with the value on success and the typed error code on every item.
+// The body starts with a 0x00 type tag, so clients that read responses into C
+// strings, such as the SourceMod transports, see it as empty.
+func formatAsBinaryKeyValue(results BatchResult, sectionName string, detected bool) keyvalues.Node {
+	section := keyvalues.NewSection(sectionName)
+	for i, item := range results.Items {
+		entry := binaryResultSection(results.itemKey(i), item)
+		if detected {
+			entry.Children = append([]keyvalues.Node{keyvalues.NewValue("format", detectedFormatName(item.Format))}, entry.Children...)
+		}
+		section.Children = append(section.Children, entry)
+	}
+	if results.Cursor != "" {
+		section.AddValue("cursor", results.Cursor)
+	}
+	if results.Summary != nil {
+		section.Children = append(section.Children, keyvalues.NewSection("summary",
+			keyvalues.NewInt("total", int32(results.Summary.Total)),
+			keyvalues.NewInt("succeeded", int32(results.Summary.Succeeded)),
+			keyvalues.NewInt("failed", int32(results.Summary.Failed)),
+			keyvalues.NewInt("duplicates", int32(results.Summary.Duplicates)),
+		))
+	}
+
+	return section
+}
+
+// itemKey is the KeyValue key of item i: its input, or its index in the whole
+// batch in positional mode.
+func (results BatchResult) itemKey(i int) string {
+	if results.Positional {
+		return strconv.Itoa(results.Offset + i)
+	}
+	return results.Items[i].Input
+}
+
+// appendTrailer adds the continuation cursor and the summary block after the
+// items when the batch has them.
+func (results BatchResult) appendTrailer(section *keyvalues.Node) {
+	if results.Cursor != "" {
+		section.AddValue("cursor", results.Cursor)
+	}
+	if results.Summary == nil {
+		return
+	}
+
+	summary := section.AddSection("summary")
+	summary.AddValue("total", strconv.Itoa(results.Summary.Total))
+	summary.AddValue("succeeded", strconv.Itoa(results.Summary.Succeeded))
+	summary.AddValue("failed", strconv.Itoa(results.Summary.Failed))
+	summary.AddValue("duplicates", strconv.Itoa(results.Summary.Duplicates))
+}
+
+func binaryResultSection(key string, item BatchItemResult) keyvalues.Node {
+	entry := keyvalues.NewSection(key)
+	if item.Error.IsValid() {
+		entry.AddValue("value", item.Value)
+		entry.Children = append(entry.Children, keyvalues.NewInt("error", ErrorNone.Code()))
+	} else {
+		entry.Children = append(entry.Children, keyvalues.NewInt("error", item.Error.Code()))
+		entry.AddValue("error_key", item.Error.Key())
+	}
+	appendIdentity(&entry, item)
+	return entry
+}
+
+func detectedFormatName(format steamIDFormat) string {
+	if format == steamIDFormatUnknown {
+		return "unknown"
+	}
+	return string(format)
+}
+
+// localizeBatchItems prepares batch items for JSON: failed items get their
+// localized message and successful ones drop ErrorNone so the field is omitted.
+func localizeBatchItems(results BatchResult, lang string) []BatchItemResult {
+	items := make([]BatchItemResult, 0, len(results.Items))
+	for _, item := range results.Items {
+		items = append(items, localizeBatchItem(item, lang))
 	}
 
-	builder.WriteString("}")
-	return builder.String()
+	return items
 }
 
-func parseBatchInput(input string) ([]string, SteamIDError) {
+func localizeBatchItem(item BatchItemResult, lang string) BatchItemResult {
+	if item.Error.IsValid() {
+		item.Error = ""
+	} else {
+		item.Message = localizedErrorMessage(item.Error, lang)
+	}
+
+	return item
+}
+
+func parseBatchInput(input string, maxItems int, batch *batchOptions) ([]string, SteamIDError, errorItem) {
 	if input == "" {
-		return nil, ErrorMissingParameter
+		return nil, ErrorMissingParameter, noErrorItem
 	}
 
-	steamids := strings.Split(input, ",")
-	if len(steamids) > appCfg.MaxBatchItems {
-		return nil, ErrorInvalidFormat
+	return validateBatchItems(strings.Split(input, ","), maxItems, batch)
+}
+
+// validateBatchItems trims every item and rejects batches over limit. Duplicates
+// fail the batch in reject mode, are collapsed and counted in dedupe mode and are
+// kept in positional mode. A rejected duplicate is returned as the failing item.
+func validateBatchItems(steamids []string, limit int, batch *batchOptions) ([]string, SteamIDError, errorItem) {
+	if len(steamids) > limit {
+		return nil, ErrorInvalidFormat, noErrorItem
 	}
 
 	seen := make(map[string]struct{}, len(steamids))
-	for i, id := range steamids {
+	kept := steamids[:0]
+	for index, id := range steamids {
 		id = strings.TrimSpace(id)
 		if _, exists := seen[id]; exists {
-			return nil, ErrorDuplicateInBatch
+			switch batch.Mode {
+			case batchModeDedupe:
+				if id != "" {
+					batch.Duplicates++
+				}
+				continue
+			case batchModeReject:
+				return nil, ErrorDuplicateInBatch, errorItem{Input: id, Index: index}
+			}
 		}
 		seen[id] = struct{}{}
-		steamids[i] = id
+		kept = append(kept, id)
 	}
-	return steamids, ErrorNone
+	return kept, ErrorNone, noErrorItem
 }
 
-func writeErrorResponse(w http.ResponseWriter, r *http.Request, err SteamIDError, responseOverride string, logContext string) {
-	w.Header().Set("Content-Type", "text/plain")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-	lang := getLang(r)
-	var statusCode int
-	var msgKey string
+// errorItem is the input that made a request fail. Index is its position in the
+// batch as submitted, or -1 when the input was not a batch entry.
+type errorItem struct {
+	Input string
+	Index int
+}
+
+var noErrorItem = errorItem{Index: -1}
+
+// errorStatus returns the HTTP status of err and the key of its localized title.
+func errorStatus(err SteamIDError) (int, string) {
 	switch err {
 	case ErrorMissingParameter:
-		statusCode = http.StatusBadRequest
-		msgKey = "missing_parameter"
+		return http.StatusBadRequest, "missing_parameter"
 	case ErrorInvalidFormat:
-		statusCode = http.StatusBadRequest
-		msgKey = "invalid_format"
+		return http.StatusBadRequest, "invalid_format"
 	case ErrorInvalidLength:
-		statusCode = http.StatusBadRequest
-		msgKey = "invalid_length"
+		return http.StatusBadRequest, "invalid_length"
 	case ErrorInvalidCharacters:
-		statusCode = http.StatusBadRequest
-		msgKey = "invalid_characters"
+		return http.StatusBadRequest, "invalid_characters"
 	case ErrorInvalidSteamID2:
-		statusCode = http.StatusBadRequest
-		msgKey = "invalid_steamid2"
+		return http.StatusBadRequest, "invalid_steamid2"
 	case ErrorInvalidSteamID3:
-		statusCode = http.StatusBadRequest
-		msgKey = "invalid_steamid3"
+		return http.StatusBadRequest, "invalid_steamid3"
 	case ErrorInvalidSteamID64:
-		statusCode = http.StatusBadRequest
-		msgKey = "invalid_steamid64"
+		return http.StatusBadRequest, "invalid_steamid64"
 	case ErrorInvalidAccountID:
-		statusCode = http.StatusBadRequest
-		msgKey = "invalid_accountid"
+		return http.StatusBadRequest, "invalid_accountid"
 	case ErrorConversionFailed:
-		statusCode = http.StatusBadRequest
-		msgKey = "conversion_failed"
+		return http.StatusBadRequest, "conversion_failed"
 	case ErrorServiceUnavailable:
-		statusCode = http.StatusServiceUnavailable
-		msgKey = "service_unavailable"
+		return http.StatusServiceUnavailable, "service_unavailable"
 	case ErrorDuplicateInBatch:
-		statusCode = http.StatusBadRequest
-		msgKey = "duplicate_in_batch"
+		return http.StatusBadRequest, "duplicate_in_batch"
+	case ErrorUnsupportedAccountType:
+		return http.StatusBadRequest, "unsupported_account_type"
+	case ErrorInvalidGroupID:
+		return http.StatusBadRequest, "invalid_groupid"
+	case ErrorInvalidUniverse:
+		return http.StatusBadRequest, "invalid_universe"
+	case ErrorUnsupportedConversion:
+		return http.StatusBadRequest, "unsupported_conversion"
+	case ErrorBodyTooLarge:
+		return http.StatusRequestEntityTooLarge, "body_too_large"
+	case ErrorJobNotFound:
+		return http.StatusNotFound, "job_not_found"
+	case ErrorInvalidEndpoint:
+		return http.StatusNotFound, "endpoint_not_found"
+	case ErrorJobInterrupted:
+		return http.StatusServiceUnavailable, "job_interrupted"
 	default:
-		statusCode = http.StatusInternalServerError
-		msgKey = "conversion_failed"
+		return http.StatusInternalServerError, "conversion_failed"
 	}
-	w.WriteHeader(statusCode)
+}
+
+// errorTitle is the localized sentence that describes err in general.
+func errorTitle(err SteamIDError, lang string) string {
+	_, msgKey := errorStatus(err)
+	if title := msg(msgKey, lang); title != msgKey {
+		return title
+	}
+	return localizedErrorMessage(err, lang)
+}
+
+func writeErrorResponse(w http.ResponseWriter, r *http.Request, err SteamIDError, responseOverride string, logContext string) {
+	writeItemErrorResponse(w, r, err, responseOverride, logContext, noErrorItem)
+}
+
+// writeItemErrorResponse is writeErrorResponse for an error caused by one input.
+// Every representation carries the error key in the X-SteamIDTools-Error header;
+// JSON clients get an RFC 9457 problem document naming the input and its index.
+func writeItemErrorResponse(w http.ResponseWriter, r *http.Request, err SteamIDError, responseOverride string, logContext string, item errorItem) {
+	lang := getLang(r)
+	statusCode, _ := errorStatus(err)
 
 	appErrorf("request failed: code=%s context=%s remote_addr=%s", err.Key(), logContext, r.RemoteAddr)
+	metrics.countError(err, errorScopeRequest)
+	spanFromContext(r.Context()).setError(err)
+
+	title := errorTitle(err, lang)
+	translated := responseOverride
+	if translated == "" {
+		translated = title
+	}
 
-	if responseOverride != "" {
-		writePlainTextBody(w, responseOverride)
+	w.Header().Set(ErrorHeader, err.Key())
+	if wantsKeyValueBinary(r) {
+		document := keyvalues.NewSection("SteamIDTools",
+			keyvalues.NewInt("error", err.Code()),
+			keyvalues.NewValue("error_key", err.Key()),
+			keyvalues.NewValue("message", translated),
+		)
+		if item.Index >= 0 || item.Input != "" {
+			document.AddValue("input", item.Input)
+		}
+		if item.Index >= 0 {
+			document.Children = append(document.Children, keyvalues.NewInt("index", int32(item.Index)))
+		}
+		writeKeyValueBinaryResponse(w, r, statusCode, document)
 		return
 	}
 
-	translated := msg(msgKey, lang)
-	if translated == msgKey {
-		translated = localizedErrorMessage(err, lang)
+	if wantsJSON(r) {
+		problem := ProblemResponse{
+			Type:    errorTypeURI(err),
+			Title:   title,
+			Status:  statusCode,
+			Detail:  translated,
+			Error:   err.Key(),
+			Message: translated,
+			Input:   item.Input,
+		}
+		if item.Index >= 0 {
+			index := item.Index
+			problem.Index = &index
+		}
+		body, encodeErr := json.Marshal(problem)
+		writeEncodedResponse(w, r, statusCode, contentTypeProblemJSON, body, encodeErr)
+		return
 	}
 
+	w.Header().Set("Content-Type", "text/plain")
+	setCORSHeader(w)
+	w.WriteHeader(statusCode)
 	writePlainTextBody(w, translated)
 }
 
+// setCORSHeader lets browsers on appCfg.CORSAllowOrigin read the response; an
+// empty origin sends no header.
+func setCORSHeader(w http.ResponseWriter) {
+	if appCfg.CORSAllowOrigin != "" {
+		w.Header().Set("Access-Control-Allow-Origin", appCfg.CORSAllowOrigin)
+	}
+}
+
 func writeSuccessResponse(w http.ResponseWriter, value string, nullterm bool) {
 	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
+	setCORSHeader(w)
 	if nullterm {
 		value = value + "\x00"
 	}
@@ -167,7 +393,7 @@ func writeSuccessResponse(w http.ResponseWriter, value string, nullterm bool) {
 
 func writeKeyValueResponse(w http.ResponseWriter, content string, nullterm bool) {
 	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
+	setCORSHeader(w)
 	if nullterm {
 		content = content + "\x00"
 	}
@@ -175,3 +401,39 @@ func writeKeyValueResponse(w http.ResponseWriter, content string, nullterm bool)
 	w.WriteHeader(http.StatusOK)
 	writePlainTextBody(w, content)
 }
+
+func writeKeyValueBinaryResponse(w http.ResponseWriter, r *http.Request, statusCode int, document keyvalues.Node) {
+	body, err := keyvalues.MarshalBinary(document)
+	writeEncodedResponse(w, r, statusCode, contentTypeKeyValueBinary, body, err)
+}
+
+func writeJSONResponse(w http.ResponseWriter, r *http.Request, statusCode int, payload any) {
+	body, err := json.Marshal(payload)
+	writeEncodedResponse(w, r, statusCode, contentTypeJSON, body, err)
+}
+
+const (
+	contentTypePlainText      = "text/plain; charset=utf-8"
+	contentTypeJSON           = "application/json; charset=utf-8"
+	contentTypeProblemJSON    = "application/problem+json; charset=utf-8"
+	contentTypeKeyValueBinary = "application/octet-stream"
+)
+
+// writeEncodedResponse writes an already encoded body, or a plain-text 500 when
+// encoding failed.
+func writeEncodedResponse(w http.ResponseWriter, r *http.Request, statusCode int, contentType string, body []byte, err error) {
+	if err != nil {
+		appErrorf("response encoding failed: content_type=%s error=%s remote_addr=%s", contentType, err.Error(), r.RemoteAddr)
+		w.Header().Set(ErrorHeader, ErrorConversionFailed.Key())
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusInternalServerError)
+		writePlainTextBody(w, localizedErrorMessage(ErrorConversionFailed, getLang(r)))
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	setCORSHeader(w)
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(body)
+}
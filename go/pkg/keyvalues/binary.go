@@ -0,0 +1,216 @@
+package keyvalues
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Binary type tags as written by Valve's KeyValues::WriteAsBinary.
+const (
+	binaryTypeSection byte = 0
+	binaryTypeString  byte = 1
+	binaryTypeInt     byte = 2
+	binaryTypeFloat   byte = 3
+	binaryTypeUint64  byte = 7
+	binaryTypeEnd     byte = 8
+)
+
+// BinaryError reports malformed binary KeyValues data.
+type BinaryError struct {
+	Offset int
+	Msg    string
+}
+
+func (e *BinaryError) Error() string {
+	return fmt.Sprintf("keyvalues: offset %d: %s", e.Offset, e.Msg)
+}
+
+// MarshalBinary encodes node in Valve's binary KeyValues format: a type tag, a
+// NUL-terminated key and the value, with every child list closed by an end tag.
+// Integers and floats are little-endian. Keys and strings must not contain NUL.
+func MarshalBinary(node Node) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := writeBinaryNode(&buf, node); err != nil {
+		return nil, err
+	}
+	buf.WriteByte(binaryTypeEnd)
+	return buf.Bytes(), nil
+}
+
+func writeBinaryNode(buf *bytes.Buffer, node Node) error {
+	if node.Section {
+		buf.WriteByte(binaryTypeSection)
+		if err := writeBinaryString(buf, node.Key); err != nil {
+			return err
+		}
+		for _, child := range node.Children {
+			if err := writeBinaryNode(buf, child); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(binaryTypeEnd)
+		return nil
+	}
+
+	switch node.Kind {
+	case KindInt:
+		value, err := strconv.ParseInt(node.Value, 10, 32)
+		if err != nil {
+			return fmt.Errorf("keyvalues: key %q: %w", node.Key, err)
+		}
+		buf.WriteByte(binaryTypeInt)
+		if err := writeBinaryString(buf, node.Key); err != nil {
+			return err
+		}
+		return binary.Write(buf, binary.LittleEndian, int32(value))
+	case KindFloat:
+		value, err := strconv.ParseFloat(node.Value, 32)
+		if err != nil {
+			return fmt.Errorf("keyvalues: key %q: %w", node.Key, err)
+		}
+		buf.WriteByte(binaryTypeFloat)
+		if err := writeBinaryString(buf, node.Key); err != nil {
+			return err
+		}
+		return binary.Write(buf, binary.LittleEndian, math.Float32bits(float32(value)))
+	case KindUint64:
+		value, err := strconv.ParseUint(node.Value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("keyvalues: key %q: %w", node.Key, err)
+		}
+		buf.WriteByte(binaryTypeUint64)
+		if err := writeBinaryString(buf, node.Key); err != nil {
+			return err
+		}
+		return binary.Write(buf, binary.LittleEndian, value)
+	default:
+		buf.WriteByte(binaryTypeString)
+		if err := writeBinaryString(buf, node.Key); err != nil {
+			return err
+		}
+		return writeBinaryString(buf, node.Value)
+	}
+}
+
+func writeBinaryString(buf *bytes.Buffer, s string) error {
+	if bytes.IndexByte([]byte(s), 0) >= 0 {
+		return fmt.Errorf("keyvalues: %q contains a NUL byte", s)
+	}
+	buf.WriteString(s)
+	buf.WriteByte(0)
+	return nil
+}
+
+// UnmarshalBinary decodes one root node written by MarshalBinary or by Valve's
+// binary writer. Integer, float and uint64 values keep their Kind.
+func UnmarshalBinary(data []byte) (Node, error) {
+	r := binaryReader{data: data}
+
+	tag, err := r.byte()
+	if err != nil {
+		return Node{}, err
+	}
+	root, err := r.node(tag)
+	if err != nil {
+		return Node{}, err
+	}
+
+	if r.pos < len(r.data) {
+		if tag, _ := r.byte(); tag != binaryTypeEnd || r.pos != len(r.data) {
+			return Node{}, r.errorf("unexpected data after root node")
+		}
+	}
+	return root, nil
+}
+
+type binaryReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *binaryReader) errorf(format string, args ...any) error {
+	return &BinaryError{Offset: r.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (r *binaryReader) byte() (byte, error) {
+	if r.pos >= len(r.data) {
+		return 0, r.errorf("unexpected end of data")
+	}
+	b := r.data[r.pos]
+	r.pos++
+	return b, nil
+}
+
+func (r *binaryReader) string() (string, error) {
+	end := bytes.IndexByte(r.data[r.pos:], 0)
+	if end < 0 {
+		return "", r.errorf("unterminated string")
+	}
+	s := string(r.data[r.pos : r.pos+end])
+	r.pos += end + 1
+	return s, nil
+}
+
+func (r *binaryReader) fixed(size int) ([]byte, error) {
+	if len(r.data)-r.pos < size {
+		return nil, r.errorf("unexpected end of data")
+	}
+	b := r.data[r.pos : r.pos+size]
+	r.pos += size
+	return b, nil
+}
+
+func (r *binaryReader) node(tag byte) (Node, error) {
+	key, err := r.string()
+	if err != nil {
+		return Node{}, err
+	}
+
+	switch tag {
+	case binaryTypeSection:
+		section := NewSection(key)
+		for {
+			childTag, err := r.byte()
+			if err != nil {
+				return Node{}, err
+			}
+			if childTag == binaryTypeEnd {
+				return section, nil
+			}
+			child, err := r.node(childTag)
+			if err != nil {
+				return Node{}, err
+			}
+			section.Children = append(section.Children, child)
+		}
+	case binaryTypeString:
+		value, err := r.string()
+		if err != nil {
+			return Node{}, err
+		}
+		return NewValue(key, value), nil
+	case binaryTypeInt:
+		b, err := r.fixed(4)
+		if err != nil {
+			return Node{}, err
+		}
+		return NewInt(key, int32(binary.LittleEndian.Uint32(b))), nil
+	case binaryTypeFloat:
+		b, err := r.fixed(4)
+		if err != nil {
+			return Node{}, err
+		}
+		value := math.Float32frombits(binary.LittleEndian.Uint32(b))
+		return Node{Key: key, Value: strconv.FormatFloat(float64(value), 'g', -1, 32), Kind: KindFloat}, nil
+	case binaryTypeUint64:
+		b, err := r.fixed(8)
+		if err != nil {
+			return Node{}, err
+		}
+		return NewUint64(key, binary.LittleEndian.Uint64(b)), nil
+	}
+	return Node{}, r.errorf("unsupported type %d for key %q", tag, key)
+}
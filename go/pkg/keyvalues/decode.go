@@ -0,0 +1,192 @@
+package keyvalues
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// SyntaxError reports malformed KeyValues text.
+type SyntaxError struct {
+	Line int
+	Msg  string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("keyvalues: line %d: %s", e.Line, e.Msg)
+}
+
+// Decode reads one root node from r.
+func Decode(r io.Reader) (Node, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Node{}, err
+	}
+	return Unmarshal(data)
+}
+
+// Unmarshal parses a document holding exactly one root node. Keys and values may
+// be quoted or bare words; // comments are ignored.
+func Unmarshal(data []byte) (Node, error) {
+	p := parser{input: string(data), line: 1}
+
+	key, err := p.next()
+	if err != nil {
+		return Node{}, err
+	}
+	if key.kind != tokenString {
+		return Node{}, p.errorf("expected root key")
+	}
+	root, err := p.parseNode(key.text)
+	if err != nil {
+		return Node{}, err
+	}
+
+	if extra, err := p.next(); err != nil {
+		return Node{}, err
+	} else if extra.kind != tokenEOF {
+		return Node{}, p.errorf("unexpected content after root node")
+	}
+	return root, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenString
+	tokenOpen
+	tokenClose
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+type parser struct {
+	input string
+	pos   int
+	line  int
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return &SyntaxError{Line: p.line, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) parseNode(key string) (Node, error) {
+	tok, err := p.next()
+	if err != nil {
+		return Node{}, err
+	}
+
+	switch tok.kind {
+	case tokenString:
+		return NewValue(key, tok.text), nil
+	case tokenOpen:
+		section := NewSection(key)
+		for {
+			child, err := p.next()
+			if err != nil {
+				return Node{}, err
+			}
+			switch child.kind {
+			case tokenClose:
+				return section, nil
+			case tokenString:
+				node, err := p.parseNode(child.text)
+				if err != nil {
+					return Node{}, err
+				}
+				section.Children = append(section.Children, node)
+			case tokenEOF:
+				return Node{}, p.errorf("unclosed section %q", key)
+			default:
+				return Node{}, p.errorf("unexpected '{' in section %q", key)
+			}
+		}
+	case tokenEOF:
+		return Node{}, p.errorf("missing value for key %q", key)
+	}
+	return Node{}, p.errorf("unexpected '}' after key %q", key)
+}
+
+func (p *parser) next() (token, error) {
+	for p.pos < len(p.input) {
+		c := p.input[p.pos]
+		switch {
+		case c == '\n':
+			p.line++
+			p.pos++
+		case c == ' ' || c == '\t' || c == '\r':
+			p.pos++
+		case c == '/' && strings.HasPrefix(p.input[p.pos:], "//"):
+			for p.pos < len(p.input) && p.input[p.pos] != '\n' {
+				p.pos++
+			}
+		case c == '{':
+			p.pos++
+			return token{kind: tokenOpen}, nil
+		case c == '}':
+			p.pos++
+			return token{kind: tokenClose}, nil
+		case c == '"':
+			return p.quoted()
+		default:
+			return p.bare(), nil
+		}
+	}
+	return token{kind: tokenEOF}, nil
+}
+
+func (p *parser) quoted() (token, error) {
+	startLine := p.line
+	p.pos++
+
+	var text strings.Builder
+	for p.pos < len(p.input) {
+		c := p.input[p.pos]
+		switch c {
+		case '"':
+			p.pos++
+			return token{kind: tokenString, text: text.String()}, nil
+		case '\\':
+			if p.pos+1 < len(p.input) {
+				switch p.input[p.pos+1] {
+				case 'n':
+					text.WriteByte('\n')
+				case 't':
+					text.WriteByte('\t')
+				case '\\':
+					text.WriteByte('\\')
+				case '"':
+					text.WriteByte('"')
+				default:
+					// Valve keeps unknown escapes verbatim.
+					text.WriteByte('\\')
+					p.pos++
+					continue
+				}
+				p.pos += 2
+				continue
+			}
+		case '\n':
+			p.line++
+		}
+		text.WriteByte(c)
+		p.pos++
+	}
+	return token{}, &SyntaxError{Line: startLine, Msg: "unterminated quoted string"}
+}
+
+func (p *parser) bare() token {
+	start := p.pos
+	for p.pos < len(p.input) {
+		c := p.input[p.pos]
+		if c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '"' || c == '{' || c == '}' {
+			break
+		}
+		p.pos++
+	}
+	return token{kind: tokenString, text: p.input[start:p.pos]}
+}
@@ -0,0 +1,77 @@
+package keyvalues
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+// DefaultIndent is the per-level indentation used by Marshal and new encoders.
+const DefaultIndent = "    "
+
+var escaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`)
+
+// Escape applies Valve's escape sequences to s.
+func Escape(s string) string {
+	return escaper.Replace(s)
+}
+
+// Encoder writes KeyValues text. The document is not newline-terminated, which
+// matches the responses SourceMod plugins already parse.
+type Encoder struct {
+	w      io.Writer
+	indent string
+}
+
+// NewEncoder returns an encoder writing to w with DefaultIndent.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w, indent: DefaultIndent}
+}
+
+// SetIndent sets the string repeated once per nesting level, for example "\t".
+func (e *Encoder) SetIndent(indent string) {
+	e.indent = indent
+}
+
+// Encode writes node as a complete document.
+func (e *Encoder) Encode(node Node) error {
+	var buf bytes.Buffer
+	e.writeNode(&buf, node, 0)
+	buf.Truncate(buf.Len() - 1)
+	_, err := e.w.Write(buf.Bytes())
+	return err
+}
+
+func (e *Encoder) writeNode(buf *bytes.Buffer, node Node, depth int) {
+	prefix := strings.Repeat(e.indent, depth)
+	buf.WriteString(prefix)
+	writeQuoted(buf, node.Key)
+	if !node.Section {
+		buf.WriteByte(' ')
+		writeQuoted(buf, node.Value)
+		buf.WriteByte('\n')
+		return
+	}
+
+	buf.WriteByte('\n')
+	buf.WriteString(prefix)
+	buf.WriteString("{\n")
+	for _, child := range node.Children {
+		e.writeNode(buf, child, depth+1)
+	}
+	buf.WriteString(prefix)
+	buf.WriteString("}\n")
+}
+
+func writeQuoted(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+	buf.WriteString(Escape(s))
+	buf.WriteByte('"')
+}
+
+// Marshal encodes node with DefaultIndent.
+func Marshal(node Node) []byte {
+	var buf bytes.Buffer
+	_ = NewEncoder(&buf).Encode(node)
+	return buf.Bytes()
+}
@@ -0,0 +1,86 @@
+// Package keyvalues reads and writes Valve KeyValues (KV1) text documents, the
+// format SourceMod's KeyValues API parses.
+//
+// Quoted strings use Valve's escape sequences: \n, \t, \\ and \". Keys are
+// matched case-insensitively, as Valve does. MarshalBinary and UnmarshalBinary
+// handle the binary variant, which keeps integer values typed.
+package keyvalues
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Kind records the type of a value node. Text documents only carry strings; the
+// binary format keeps integers and floats typed.
+type Kind byte
+
+const (
+	KindString Kind = iota
+	KindInt
+	KindFloat
+	KindUint64
+)
+
+// Node is either a value (Section false) or a section holding ordered children.
+// Value always holds the text form, whatever the Kind.
+type Node struct {
+	Key      string
+	Value    string
+	Kind     Kind
+	Section  bool
+	Children []Node
+}
+
+// NewSection returns a section node with the given children.
+func NewSection(key string, children ...Node) Node {
+	return Node{Key: key, Section: true, Children: children}
+}
+
+// NewValue returns a string value node.
+func NewValue(key, value string) Node {
+	return Node{Key: key, Value: value}
+}
+
+// NewInt returns a 32-bit integer value node.
+func NewInt(key string, value int32) Node {
+	return Node{Key: key, Value: strconv.FormatInt(int64(value), 10), Kind: KindInt}
+}
+
+// NewUint64 returns an unsigned 64-bit value node.
+func NewUint64(key string, value uint64) Node {
+	return Node{Key: key, Value: strconv.FormatUint(value, 10), Kind: KindUint64}
+}
+
+// AddValue appends a string value to a section.
+func (n *Node) AddValue(key, value string) {
+	n.Section = true
+	n.Children = append(n.Children, NewValue(key, value))
+}
+
+// AddSection appends a child section and returns it for further population. The
+// pointer is only valid until the next child is added to n.
+func (n *Node) AddSection(key string) *Node {
+	n.Section = true
+	n.Children = append(n.Children, NewSection(key))
+	return &n.Children[len(n.Children)-1]
+}
+
+// Get returns the first child whose key matches key.
+func (n Node) Get(key string) (Node, bool) {
+	for _, child := range n.Children {
+		if strings.EqualFold(child.Key, key) {
+			return child, true
+		}
+	}
+	return Node{}, false
+}
+
+// String returns the value of the first child value named key, or "" when absent.
+func (n Node) String(key string) string {
+	child, ok := n.Get(key)
+	if !ok || child.Section {
+		return ""
+	}
+	return child.Value
+}
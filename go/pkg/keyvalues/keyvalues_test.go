@@ -0,0 +1,194 @@
+package keyvalues
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMarshalEscapesAndNests(t *testing.T) {
+	t.Parallel()
+
+	root := NewSection("SteamIDTools",
+		NewValue("plain", "76561197960287930"),
+		NewValue(`say "hi"`, "C:\\path\nnext\tcol"),
+		NewSection("[U:1:22202]", NewValue("format", "sid3")),
+	)
+
+	expected := "\"SteamIDTools\"\n{\n" +
+		"    \"plain\" \"76561197960287930\"\n" +
+		"    \"say \\\"hi\\\"\" \"C:\\\\path\\nnext\\tcol\"\n" +
+		"    \"[U:1:22202]\"\n" +
+		"    {\n" +
+		"        \"format\" \"sid3\"\n" +
+		"    }\n" +
+		"}"
+	if got := string(Marshal(root)); got != expected {
+		t.Fatalf("unexpected document:\n%s", got)
+	}
+}
+
+func TestEncoderIndent(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	encoder := NewEncoder(&buf)
+	encoder.SetIndent("\t")
+	if err := encoder.Encode(NewSection("root", NewSection("child", NewValue("k", "v")))); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+
+	expected := "\"root\"\n{\n\t\"child\"\n\t{\n\t\t\"k\" \"v\"\n\t}\n}"
+	if got := buf.String(); got != expected {
+		t.Fatalf("unexpected document:\n%q", got)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	root := NewSection("root",
+		NewValue("quote\"back\\slash", "line\nbreak\ttab"),
+		NewSection("empty"),
+		NewSection("nested", NewValue("", "")),
+	)
+
+	decoded, err := Unmarshal(Marshal(root))
+	if err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, root) {
+		t.Fatalf("round trip mismatch:\n got %+v\nwant %+v", decoded, root)
+	}
+}
+
+func TestUnmarshalValveSyntax(t *testing.T) {
+	t.Parallel()
+
+	input := "// header comment\n" +
+		"\"Root\" {\n" +
+		"\tbare_key bare_value // trailing comment\n" +
+		"\t\"unknown\" \"a\\qb\"\n" +
+		"\tSection\n\t{\n\t\t\"Key\" \"1\"\n\t}\n" +
+		"}\n"
+
+	root, err := Decode(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got := root.String("BARE_KEY"); got != "bare_value" {
+		t.Fatalf("expected case-insensitive lookup, got %q", got)
+	}
+	if got := root.String("unknown"); got != `a\qb` {
+		t.Fatalf("expected unknown escape kept verbatim, got %q", got)
+	}
+	section, ok := root.Get("section")
+	if !ok || !section.Section || section.String("key") != "1" {
+		t.Fatalf("unexpected section %+v", section)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name  string
+		input string
+		line  int
+	}{
+		{name: "empty", input: "", line: 1},
+		{name: "unterminated string", input: "\"root\"\n{\n\"k\" \"v", line: 3},
+		{name: "unclosed section", input: "\"root\"\n{\n\"k\" \"v\"\n", line: 4},
+		{name: "missing value", input: "\"root\" { \"k\" }", line: 1},
+		{name: "trailing content", input: "\"a\" \"1\"\n\"b\" \"2\"", line: 2},
+		{name: "stray close", input: "\"root\" }", line: 1},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Unmarshal([]byte(tc.input))
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("expected SyntaxError, got %v", err)
+			}
+			if syntaxErr.Line != tc.line {
+				t.Fatalf("expected line %d, got %d (%v)", tc.line, syntaxErr.Line, err)
+			}
+		})
+	}
+}
+
+func TestMarshalBinaryLayout(t *testing.T) {
+	t.Parallel()
+
+	data, err := MarshalBinary(NewSection("r", NewValue("k", "v"), NewInt("e", 258)))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	expected := []byte{
+		0, 'r', 0,
+		1, 'k', 0, 'v', 0,
+		2, 'e', 0, 0x02, 0x01, 0x00, 0x00,
+		8,
+		8,
+	}
+	if !bytes.Equal(data, expected) {
+		t.Fatalf("unexpected bytes %v", data)
+	}
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	root := NewSection("SteamIDTools",
+		NewSection("quote\"and\nnewline",
+			NewValue("value", "[U:1:22202]"),
+			NewInt("error", -1),
+		),
+		NewUint64("steamid64", 76561197960287930),
+		Node{Key: "ratio", Value: "0.5", Kind: KindFloat},
+		NewSection("empty"),
+	)
+
+	data, err := MarshalBinary(root)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	decoded, err := UnmarshalBinary(data)
+	if err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, root) {
+		t.Fatalf("round trip mismatch:\n got %+v\nwant %+v", decoded, root)
+	}
+}
+
+func TestBinaryErrors(t *testing.T) {
+	t.Parallel()
+
+	if _, err := MarshalBinary(NewSection("r", NewValue("k", "a\x00b"))); err == nil {
+		t.Fatal("expected NUL byte in value to be rejected")
+	}
+	if _, err := MarshalBinary(NewSection("r", Node{Key: "n", Value: "x", Kind: KindInt})); err == nil {
+		t.Fatal("expected non-numeric int value to be rejected")
+	}
+
+	for _, data := range [][]byte{
+		{},
+		{0, 'r', 0, 1, 'k', 0, 'v'},
+		{0, 'r', 0, 2, 'e', 0, 1, 2},
+		{0, 'r', 0, 6, 'c', 0, 1, 2, 3, 4, 8, 8},
+		{0, 'r', 0, 8, 8, 0},
+	} {
+		var binErr *BinaryError
+		if _, err := UnmarshalBinary(data); !errors.As(err, &binErr) {
+			t.Fatalf("expected BinaryError for %v, got %v", data, err)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package steamid
+
+// Error is the error type returned by every parse and format function in this
+// package. Its values are the same machine-readable keys the HTTP service
+// reports, so callers can compare them directly or with errors.Is.
+type Error string
+
+const (
+	ErrInvalidFormat          Error = "invalid_format"
+	ErrInvalidLength          Error = "invalid_length"
+	ErrInvalidCharacters      Error = "invalid_characters"
+	ErrInvalidSteamID2        Error = "invalid_steamid2"
+	ErrInvalidSteamID3        Error = "invalid_steamid3"
+	ErrInvalidSteamID64       Error = "invalid_steamid64"
+	ErrInvalidAccountID       Error = "invalid_accountid"
+	ErrInvalidGroupID         Error = "invalid_groupid"
+	ErrInvalidUniverse        Error = "invalid_universe"
+	ErrUnsupportedAccountType Error = "unsupported_account_type"
+)
+
+func (e Error) Error() string { return string(e) }
@@ -0,0 +1,56 @@
+package steamid
+
+import "strconv"
+
+// FormatAccountID renders the 32-bit account ID.
+func FormatAccountID(id SteamID) string {
+	return strconv.FormatUint(uint64(id.AccountID), 10)
+}
+
+// FormatSteamID2 renders STEAM_X:Y:Z for individual accounts. publicUniverse is
+// the digit used for public-universe accounts and must be UniverseInvalid (STEAM_0)
+// or UniversePublic (STEAM_1); other universes always render their own number.
+func FormatSteamID2(id SteamID, publicUniverse Universe) (string, error) {
+	if publicUniverse != UniverseInvalid && publicUniverse != UniversePublic {
+		return "", ErrInvalidUniverse
+	}
+	if id.Type != AccountTypeIndividual {
+		return "", ErrUnsupportedAccountType
+	}
+	universe := publicUniverse
+	if id.Universe != UniversePublic {
+		universe = id.Universe
+	}
+	y := id.AccountID & 1
+	z := id.AccountID >> 1
+	return "STEAM_" + strconv.FormatUint(uint64(universe), 10) + ":" + strconv.FormatUint(uint64(y), 10) + ":" + strconv.FormatUint(uint64(z), 10), nil
+}
+
+// FormatSteamID3 renders [L:U:A], adding the instance for account types that need it.
+func FormatSteamID3(id SteamID) (string, error) {
+	letter, ok := id.steamID3Letter()
+	if !ok {
+		return "", ErrUnsupportedAccountType
+	}
+	steamid3 := "[" + string(letter) + ":" + strconv.FormatUint(uint64(id.Universe), 10) + ":" + strconv.FormatUint(uint64(id.AccountID), 10)
+	if id.steamID3RendersInstance() {
+		steamid3 += ":" + strconv.FormatUint(uint64(id.Instance), 10)
+	}
+	return steamid3 + "]", nil
+}
+
+// FormatHex renders steam:<lowercase hex>.
+func FormatHex(id SteamID) string {
+	return HexPrefix + strconv.FormatUint(id.Uint64(), 16)
+}
+
+// FormatProfileURL renders the steamcommunity.com URL for individual and clan accounts.
+func FormatProfileURL(id SteamID) (string, error) {
+	switch id.Type {
+	case AccountTypeIndividual:
+		return ProfileURLPrefix + id.SteamID64(), nil
+	case AccountTypeClan:
+		return GroupProfileURLPrefix + id.SteamID64(), nil
+	}
+	return "", ErrUnsupportedAccountType
+}
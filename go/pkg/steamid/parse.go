@@ -0,0 +1,241 @@
+package steamid
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	SteamCommunityHost    = "steamcommunity.com"
+	HexPrefix             = "steam:"
+	ProfileURLPrefix      = "https://" + SteamCommunityHost + "/profiles/"
+	GroupProfileURLPrefix = "https://" + SteamCommunityHost + "/gid/"
+)
+
+func isValidAccountID(accountID uint64) bool {
+	return accountID > 0 && accountID <= MaxAccountID
+}
+
+// IsASCIIUnsignedDecimal reports whether value consists only of the digits 0-9.
+func IsASCIIUnsignedDecimal(value string) bool {
+	for i := 0; i < len(value); i++ {
+		if value[i] < '0' || value[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ParseSteamID64 decodes a decimal SteamID64 of any account type.
+func ParseSteamID64(steamid64Str string) (SteamID, error) {
+	if len(steamid64Str) < minSteamID64Length || len(steamid64Str) > maxSteamID64Length {
+		return SteamID{}, ErrInvalidLength
+	}
+	if !IsASCIIUnsignedDecimal(steamid64Str) {
+		return SteamID{}, ErrInvalidCharacters
+	}
+	value, err := strconv.ParseUint(steamid64Str, 10, 64)
+	if err != nil {
+		return SteamID{}, ErrInvalidSteamID64
+	}
+	id := FromUint64(value)
+	if !id.IsValid() {
+		return SteamID{}, ErrInvalidSteamID64
+	}
+	return id, nil
+}
+
+// ParseAccountID decodes a decimal 32-bit account ID. Zero is rejected.
+func ParseAccountID(accountIDStr string) (uint32, error) {
+	if len(accountIDStr) == 0 {
+		return 0, ErrInvalidLength
+	}
+	if !IsASCIIUnsignedDecimal(accountIDStr) {
+		return 0, ErrInvalidCharacters
+	}
+	accountID, err := strconv.ParseUint(accountIDStr, 10, 64)
+	if err != nil || !isValidAccountID(accountID) {
+		return 0, ErrInvalidAccountID
+	}
+	return uint32(accountID), nil
+}
+
+// ParseSteamID2 decodes STEAM_X:Y:Z. SteamID2 only describes individual desktop
+// accounts; STEAM_0 is read as the public universe.
+func ParseSteamID2(steamid2 string) (SteamID, error) {
+	if len(steamid2) < 11 {
+		return SteamID{}, ErrInvalidLength
+	}
+	if !strings.HasPrefix(steamid2, "STEAM_") {
+		return SteamID{}, ErrInvalidSteamID2
+	}
+	parts := strings.Split(steamid2, ":")
+	if len(parts) != 3 {
+		return SteamID{}, ErrInvalidSteamID2
+	}
+	universe, err := strconv.ParseUint(parts[0][6:], 10, 8)
+	if err != nil || universe > uint64(UniverseDev) {
+		return SteamID{}, ErrInvalidSteamID2
+	}
+	y, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil || (y != 0 && y != 1) {
+		return SteamID{}, ErrInvalidSteamID2
+	}
+	z, err := strconv.ParseUint(parts[2], 10, 64)
+	if err != nil {
+		return SteamID{}, ErrInvalidSteamID2
+	}
+	if z > (MaxAccountID-y)/2 {
+		return SteamID{}, ErrInvalidSteamID2
+	}
+	accountID := z*2 + y
+	if !isValidAccountID(accountID) {
+		return SteamID{}, ErrInvalidSteamID2
+	}
+	// Source engine games render the public universe as STEAM_0.
+	if universe == uint64(UniverseInvalid) {
+		universe = uint64(UniversePublic)
+	}
+	return NewIndividual(Universe(universe), uint32(accountID)), nil
+}
+
+// ParseSteamID3 decodes [L:U:A] or [L:U:A:I] for every account type letter.
+func ParseSteamID3(steamid3 string) (SteamID, error) {
+	if len(steamid3) < 7 {
+		return SteamID{}, ErrInvalidLength
+	}
+	if steamid3[0] != '[' || steamid3[2] != ':' || !strings.HasSuffix(steamid3, "]") {
+		return SteamID{}, ErrInvalidSteamID3
+	}
+	accountType, instance, ok := accountTypeFromSteamID3Letter(steamid3[1])
+	if !ok {
+		return SteamID{}, ErrInvalidSteamID3
+	}
+	parts := strings.Split(steamid3[3:len(steamid3)-1], ":")
+	if len(parts) != 2 && len(parts) != 3 {
+		return SteamID{}, ErrInvalidSteamID3
+	}
+	universe, err := strconv.ParseUint(parts[0], 10, 8)
+	if err != nil {
+		return SteamID{}, ErrInvalidSteamID3
+	}
+	if !IsASCIIUnsignedDecimal(parts[1]) {
+		return SteamID{}, ErrInvalidCharacters
+	}
+	accountID, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return SteamID{}, ErrInvalidSteamID3
+	}
+	if len(parts) == 3 {
+		if !IsASCIIUnsignedDecimal(parts[2]) {
+			return SteamID{}, ErrInvalidCharacters
+		}
+		explicitInstance, err := strconv.ParseUint(parts[2], 10, 32)
+		if err != nil || explicitInstance > uint64(MaxInstance) {
+			return SteamID{}, ErrInvalidSteamID3
+		}
+		if accountType == AccountTypeChat {
+			instance |= uint32(explicitInstance)
+		} else {
+			instance = uint32(explicitInstance)
+		}
+	}
+
+	id := SteamID{
+		Universe:  Universe(universe),
+		Type:      accountType,
+		Instance:  instance,
+		AccountID: uint32(accountID),
+	}
+	if !id.IsValid() {
+		return SteamID{}, ErrInvalidSteamID3
+	}
+	return id, nil
+}
+
+// ParseHex decodes steam:<hex> or 0x<hex>.
+func ParseHex(steamidHex string) (SteamID, error) {
+	var digits string
+	switch {
+	case strings.HasPrefix(steamidHex, HexPrefix):
+		digits = steamidHex[len(HexPrefix):]
+	case strings.HasPrefix(steamidHex, "0x"), strings.HasPrefix(steamidHex, "0X"):
+		digits = steamidHex[2:]
+	default:
+		return SteamID{}, ErrInvalidFormat
+	}
+	if len(digits) == 0 || len(digits) > 16 {
+		return SteamID{}, ErrInvalidLength
+	}
+	value, err := strconv.ParseUint(digits, 16, 64)
+	if err != nil {
+		return SteamID{}, ErrInvalidCharacters
+	}
+	id := FromUint64(value)
+	if !id.IsValid() {
+		return SteamID{}, ErrInvalidSteamID64
+	}
+	return id, nil
+}
+
+// ParseProfileURL decodes steamcommunity.com /profiles/<id> and /gid/<id> URLs.
+// Vanity URLs need a Steam Web API lookup and are rejected.
+func ParseProfileURL(profileURL string) (SteamID, error) {
+	if !strings.Contains(profileURL, "://") {
+		profileURL = "https://" + profileURL
+	}
+	parsed, err := url.Parse(profileURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return SteamID{}, ErrInvalidFormat
+	}
+	host := strings.TrimPrefix(strings.ToLower(parsed.Host), "www.")
+	if host != SteamCommunityHost {
+		return SteamID{}, ErrInvalidFormat
+	}
+	segments := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	if len(segments) != 2 {
+		return SteamID{}, ErrInvalidFormat
+	}
+
+	switch segments[0] {
+	case "profiles":
+		return ParseSteamID64(segments[1])
+	case "gid":
+		return ParseGroupSteamID64(segments[1])
+	}
+	return SteamID{}, ErrInvalidFormat
+}
+
+// ParseGroupSteamID64 decodes a SteamID64 and requires a clan account.
+func ParseGroupSteamID64(groupid64Str string) (SteamID, error) {
+	return requireClan(ParseSteamID64(groupid64Str))
+}
+
+// ParseGroupSteamID3 decodes a SteamID3 and requires a clan account ([g:U:A]).
+func ParseGroupSteamID3(groupid3 string) (SteamID, error) {
+	return requireClan(ParseSteamID3(groupid3))
+}
+
+func requireClan(id SteamID, err error) (SteamID, error) {
+	if err != nil {
+		return SteamID{}, err
+	}
+	if id.Type != AccountTypeClan {
+		return SteamID{}, ErrInvalidGroupID
+	}
+	return id, nil
+}
+
+// ParseSteamID2Universe reads the digit rendered for public-universe accounts in
+// SteamID2: "0" (Source engine games) or "1".
+func ParseSteamID2Universe(universe string) (Universe, error) {
+	switch universe {
+	case "0":
+		return UniverseInvalid, nil
+	case "1":
+		return UniversePublic, nil
+	}
+	return UniverseInvalid, ErrInvalidUniverse
+}
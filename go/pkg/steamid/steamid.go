@@ -0,0 +1,260 @@
+// Package steamid parses and formats Steam identifiers.
+//
+// Every format decodes to a SteamID, the 64-bit value split into universe,
+// account type, instance and account ID. Nothing in this package reads
+// configuration: the universe rendered in SteamID2 is always an argument.
+package steamid
+
+import "strconv"
+
+// Universe is the 8-bit universe field of a 64-bit SteamID.
+type Universe uint8
+
+const (
+	UniverseInvalid  Universe = 0
+	UniversePublic   Universe = 1
+	UniverseBeta     Universe = 2
+	UniverseInternal Universe = 3
+	UniverseDev      Universe = 4
+)
+
+var universeNames = map[Universe]string{
+	UniverseInvalid:  "invalid",
+	UniversePublic:   "public",
+	UniverseBeta:     "beta",
+	UniverseInternal: "internal",
+	UniverseDev:      "dev",
+}
+
+func (u Universe) String() string {
+	if name, ok := universeNames[u]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// AccountType is the 4-bit account type field of a 64-bit SteamID.
+type AccountType uint8
+
+const (
+	AccountTypeInvalid        AccountType = 0
+	AccountTypeIndividual     AccountType = 1
+	AccountTypeMultiseat      AccountType = 2
+	AccountTypeGameServer     AccountType = 3
+	AccountTypeAnonGameServer AccountType = 4
+	AccountTypePending        AccountType = 5
+	AccountTypeContentServer  AccountType = 6
+	AccountTypeClan           AccountType = 7
+	AccountTypeChat           AccountType = 8
+	AccountTypeP2PSuperSeeder AccountType = 9
+	AccountTypeAnonUser       AccountType = 10
+)
+
+var accountTypeNames = map[AccountType]string{
+	AccountTypeInvalid:        "invalid",
+	AccountTypeIndividual:     "individual",
+	AccountTypeMultiseat:      "multiseat",
+	AccountTypeGameServer:     "gameserver",
+	AccountTypeAnonGameServer: "anon_gameserver",
+	AccountTypePending:        "pending",
+	AccountTypeContentServer:  "content_server",
+	AccountTypeClan:           "clan",
+	AccountTypeChat:           "chat",
+	AccountTypeP2PSuperSeeder: "p2p_superseeder",
+	AccountTypeAnonUser:       "anon_user",
+}
+
+func (t AccountType) String() string {
+	if name, ok := accountTypeNames[t]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+const (
+	InstanceAll     = uint32(0)
+	InstanceDesktop = uint32(1)
+	InstanceConsole = uint32(2)
+	InstanceWeb     = uint32(4)
+
+	MaxInstance = uint32((1 << 20) - 1)
+
+	// Chat instance flags occupy the top bits of the instance field.
+	ChatInstanceFlagClan     = (MaxInstance + 1) >> 1
+	ChatInstanceFlagLobby    = (MaxInstance + 1) >> 2
+	ChatInstanceFlagMMSLobby = (MaxInstance + 1) >> 3
+)
+
+const (
+	// BaseSteamID64 is the SteamID64 of account ID 0 in the public universe.
+	BaseSteamID64 = uint64(76561197960265728)
+	MaxAccountID  = uint64((1 << 32) - 1)
+)
+
+// steamID3Letters maps account types to the letters Valve uses in SteamID3.
+// Chat IDs carry extra letters ('c' clan chat, 'L' lobby) selected by instance flags.
+var steamID3Letters = map[AccountType]byte{
+	AccountTypeInvalid:        'I',
+	AccountTypeIndividual:     'U',
+	AccountTypeMultiseat:      'M',
+	AccountTypeGameServer:     'G',
+	AccountTypeAnonGameServer: 'A',
+	AccountTypePending:        'P',
+	AccountTypeContentServer:  'C',
+	AccountTypeClan:           'g',
+	AccountTypeChat:           'T',
+	AccountTypeAnonUser:       'a',
+}
+
+const (
+	accountIDBits = 32
+	instanceBits  = 20
+	typeBits      = 4
+
+	instanceShift = accountIDBits
+	typeShift     = instanceShift + instanceBits
+	universeShift = typeShift + typeBits
+
+	minSteamID64Length = 17
+	maxSteamID64Length = 20
+)
+
+// SteamID is the decoded form of a 64-bit SteamID:
+// universe (8 bits), account type (4 bits), instance (20 bits) and account ID (32 bits).
+type SteamID struct {
+	Universe  Universe
+	Type      AccountType
+	Instance  uint32
+	AccountID uint32
+}
+
+// NewIndividual returns the desktop-instance individual account for accountID in universe.
+func NewIndividual(universe Universe, accountID uint32) SteamID {
+	return SteamID{
+		Universe:  universe,
+		Type:      AccountTypeIndividual,
+		Instance:  InstanceDesktop,
+		AccountID: accountID,
+	}
+}
+
+// NewClan returns the group (clan) SteamID for accountID in universe.
+func NewClan(universe Universe, accountID uint32) SteamID {
+	return SteamID{
+		Universe:  universe,
+		Type:      AccountTypeClan,
+		Instance:  InstanceAll,
+		AccountID: accountID,
+	}
+}
+
+// FromUint64 splits a raw 64-bit value into its fields without validating them.
+func FromUint64(value uint64) SteamID {
+	return SteamID{
+		Universe:  Universe(value >> universeShift),
+		Type:      AccountType((value >> typeShift) & ((1 << typeBits) - 1)),
+		Instance:  uint32((value >> instanceShift) & uint64(MaxInstance)),
+		AccountID: uint32(value & MaxAccountID),
+	}
+}
+
+// Uint64 packs the fields back into a 64-bit SteamID.
+func (id SteamID) Uint64() uint64 {
+	return uint64(id.Universe)<<universeShift |
+		uint64(id.Type&((1<<typeBits)-1))<<typeShift |
+		uint64(id.Instance&MaxInstance)<<instanceShift |
+		uint64(id.AccountID)
+}
+
+// SteamID64 renders the decimal 64-bit form.
+func (id SteamID) SteamID64() string {
+	return strconv.FormatUint(id.Uint64(), 10)
+}
+
+// String renders the decimal 64-bit form.
+func (id SteamID) String() string {
+	return id.SteamID64()
+}
+
+// Account returns id with an individual's instance reset to InstanceDesktop, so
+// the same player reached through desktop, console or web IDs compares equal.
+// Other account types keep their instance, which carries chat and lobby flags.
+func (id SteamID) Account() SteamID {
+	if id.Type == AccountTypeIndividual {
+		id.Instance = InstanceDesktop
+	}
+	return id
+}
+
+// IsValid applies the same field checks Valve's CSteamID uses.
+func (id SteamID) IsValid() bool {
+	if id.Type <= AccountTypeInvalid || id.Type > AccountTypeAnonUser {
+		return false
+	}
+	if id.Universe <= UniverseInvalid || id.Universe > UniverseDev {
+		return false
+	}
+	if id.Instance > MaxInstance {
+		return false
+	}
+
+	switch id.Type {
+	case AccountTypeIndividual:
+		return id.AccountID != 0 && id.Instance <= InstanceWeb
+	case AccountTypeClan:
+		return id.AccountID != 0 && id.Instance == InstanceAll
+	case AccountTypeGameServer:
+		return id.AccountID != 0
+	case AccountTypeAnonGameServer:
+		return id.AccountID != 0 || id.Instance != InstanceAll
+	}
+
+	return true
+}
+
+// steamID3Letter returns the SteamID3 letter for id, honoring chat instance flags.
+func (id SteamID) steamID3Letter() (byte, bool) {
+	if id.Type == AccountTypeChat {
+		switch {
+		case id.Instance&ChatInstanceFlagClan != 0:
+			return 'c', true
+		case id.Instance&ChatInstanceFlagLobby != 0:
+			return 'L', true
+		}
+	}
+
+	letter, ok := steamID3Letters[id.Type]
+	return letter, ok
+}
+
+// steamID3RendersInstance reports whether the instance must be written as a fourth SteamID3 component.
+func (id SteamID) steamID3RendersInstance() bool {
+	switch id.Type {
+	case AccountTypeAnonGameServer, AccountTypeMultiseat:
+		return true
+	case AccountTypeIndividual:
+		return id.Instance != InstanceDesktop
+	}
+
+	return false
+}
+
+// accountTypeFromSteamID3Letter returns the account type and default instance for a SteamID3 letter.
+func accountTypeFromSteamID3Letter(letter byte) (AccountType, uint32, bool) {
+	switch letter {
+	case 'U':
+		return AccountTypeIndividual, InstanceDesktop, true
+	case 'c':
+		return AccountTypeChat, ChatInstanceFlagClan, true
+	case 'L':
+		return AccountTypeChat, ChatInstanceFlagLobby, true
+	}
+
+	for accountType, typeLetter := range steamID3Letters {
+		if typeLetter == letter && accountType != AccountTypeInvalid {
+			return accountType, InstanceAll, true
+		}
+	}
+
+	return AccountTypeInvalid, InstanceAll, false
+}
@@ -0,0 +1,239 @@
+package steamid
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseSteamID64DecodesAllAccountTypes(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name  string
+		input string
+		want  SteamID
+	}{
+		{
+			name:  "public individual",
+			input: "76561197960287930",
+			want:  SteamID{Universe: UniversePublic, Type: AccountTypeIndividual, Instance: InstanceDesktop, AccountID: 22202},
+		},
+		{
+			name:  "beta universe individual",
+			input: "148618791998215866",
+			want:  SteamID{Universe: UniverseBeta, Type: AccountTypeIndividual, Instance: InstanceDesktop, AccountID: 22202},
+		},
+		{
+			name:  "clan",
+			input: "103582791429521412",
+			want:  SteamID{Universe: UniversePublic, Type: AccountTypeClan, Instance: InstanceAll, AccountID: 4},
+		},
+		{
+			name:  "game server",
+			input: "85568397215006725",
+			want:  SteamID{Universe: UniversePublic, Type: AccountTypeGameServer, Instance: 1, AccountID: 5},
+		},
+		{
+			name:  "anonymous game server",
+			input: "90077292537053189",
+			want:  SteamID{Universe: UniversePublic, Type: AccountTypeAnonGameServer, Instance: 1234, AccountID: 5},
+		},
+		{
+			name:  "chat",
+			input: "110338190870577157",
+			want:  SteamID{Universe: UniversePublic, Type: AccountTypeChat, Instance: 0x80000, AccountID: 5},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseSteamID64(tc.input)
+			if err != nil {
+				t.Fatalf("expected no error, got %q", err)
+			}
+			if got != tc.want {
+				t.Fatalf("expected %+v, got %+v", tc.want, got)
+			}
+			if encoded := got.SteamID64(); encoded != tc.input {
+				t.Fatalf("expected re-encoded %q, got %q", tc.input, encoded)
+			}
+		})
+	}
+}
+
+func TestParseSteamID64RejectsInvalidFields(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		input   string
+		wantErr Error
+	}{
+		{
+			name:    "clan with non-zero instance",
+			input:   "103582795724488708",
+			wantErr: ErrInvalidSteamID64,
+		},
+		{
+			name:    "unknown universe",
+			input:   "364791574111977473",
+			wantErr: ErrInvalidSteamID64,
+		},
+		{
+			name:    "too short",
+			input:   "7656119796028793",
+			wantErr: ErrInvalidLength,
+		},
+		{
+			name:    "overflows uint64",
+			input:   "99999999999999999999",
+			wantErr: ErrInvalidSteamID64,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := ParseSteamID64(tc.input); !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected error %q, got %q", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestSteamID3RoundTripsEveryAccountTypeLetter(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		input     string
+		canonical string
+		wantType  AccountType
+	}{
+		{input: "[U:1:22202]", canonical: "[U:1:22202]", wantType: AccountTypeIndividual},
+		{input: "[U:1:22202:1]", canonical: "[U:1:22202]", wantType: AccountTypeIndividual},
+		{input: "[U:1:22202:4]", canonical: "[U:1:22202:4]", wantType: AccountTypeIndividual},
+		{input: "[g:1:4]", canonical: "[g:1:4]", wantType: AccountTypeClan},
+		{input: "[G:1:123]", canonical: "[G:1:123]", wantType: AccountTypeGameServer},
+		{input: "[A:1:123:456]", canonical: "[A:1:123:456]", wantType: AccountTypeAnonGameServer},
+		{input: "[M:1:123:2]", canonical: "[M:1:123:2]", wantType: AccountTypeMultiseat},
+		{input: "[T:1:5]", canonical: "[T:1:5]", wantType: AccountTypeChat},
+		{input: "[c:1:5]", canonical: "[c:1:5]", wantType: AccountTypeChat},
+		{input: "[L:1:5]", canonical: "[L:1:5]", wantType: AccountTypeChat},
+		{input: "[a:1:5]", canonical: "[a:1:5]", wantType: AccountTypeAnonUser},
+		{input: "[U:4:22202]", canonical: "[U:4:22202]", wantType: AccountTypeIndividual},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.input, func(t *testing.T) {
+			t.Parallel()
+
+			id, err := ParseSteamID3(tc.input)
+			if err != nil {
+				t.Fatalf("expected no error, got %q", err)
+			}
+			if id.Type != tc.wantType {
+				t.Fatalf("expected type %d, got %d", tc.wantType, id.Type)
+			}
+
+			decoded, err := ParseSteamID64(id.SteamID64())
+			if err != nil || decoded != id {
+				t.Fatalf("expected %+v to survive a SteamID64 round trip, got %+v (%v)", id, decoded, err)
+			}
+
+			sid3, err := FormatSteamID3(id)
+			if err != nil || sid3 != tc.canonical {
+				t.Fatalf("expected %q, got %q (%v)", tc.canonical, sid3, err)
+			}
+		})
+	}
+}
+
+func TestParseSteamID3RejectsInvalidInput(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		input   string
+		wantErr Error
+	}{
+		{input: "[X:1:5]", wantErr: ErrInvalidSteamID3},
+		{input: "[g:1:4:1]", wantErr: ErrInvalidSteamID3},
+		{input: "[U:9:22202]", wantErr: ErrInvalidSteamID3},
+		{input: "[U:1:0]", wantErr: ErrInvalidSteamID3},
+		{input: "[U:1:abc]", wantErr: ErrInvalidCharacters},
+		{input: "[U:1:5:6:7]", wantErr: ErrInvalidSteamID3},
+		{input: "[U:1]", wantErr: ErrInvalidLength},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.input, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := ParseSteamID3(tc.input); !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected error %q, got %q", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestFormatSteamID2TakesUniverseExplicitly(t *testing.T) {
+	t.Parallel()
+
+	id := NewIndividual(UniversePublic, 22202)
+	if got, err := FormatSteamID2(id, UniverseInvalid); err != nil || got != "STEAM_0:0:11101" {
+		t.Fatalf("expected STEAM_0:0:11101, got %q (%v)", got, err)
+	}
+	if got, err := FormatSteamID2(id, UniversePublic); err != nil || got != "STEAM_1:0:11101" {
+		t.Fatalf("expected STEAM_1:0:11101, got %q (%v)", got, err)
+	}
+	if got, err := FormatSteamID2(NewIndividual(UniverseBeta, 22202), UniverseInvalid); err != nil || got != "STEAM_2:0:11101" {
+		t.Fatalf("expected beta accounts to keep their universe, got %q (%v)", got, err)
+	}
+	if _, err := FormatSteamID2(id, UniverseDev); !errors.Is(err, ErrInvalidUniverse) {
+		t.Fatalf("expected %q, got %v", ErrInvalidUniverse, err)
+	}
+	if _, err := FormatSteamID2(NewClan(UniversePublic, 4), UniversePublic); !errors.Is(err, ErrUnsupportedAccountType) {
+		t.Fatalf("expected %q, got %v", ErrUnsupportedAccountType, err)
+	}
+}
+
+func TestParseGroupIDsRequireClanAccounts(t *testing.T) {
+	t.Parallel()
+
+	if _, err := ParseGroupSteamID64("76561197960287930"); !errors.Is(err, ErrInvalidGroupID) {
+		t.Fatalf("expected %q for an individual SteamID64, got %v", ErrInvalidGroupID, err)
+	}
+	id, err := ParseGroupSteamID3("[g:1:4]")
+	if err != nil || id != NewClan(UniversePublic, 4) {
+		t.Fatalf("unexpected clan %+v (%v)", id, err)
+	}
+	if url, err := FormatProfileURL(id); err != nil || url != GroupProfileURLPrefix+"103582791429521412" {
+		t.Fatalf("unexpected group profile URL %q (%v)", url, err)
+	}
+}
+
+func TestAccountNormalizesIndividualInstance(t *testing.T) {
+	t.Parallel()
+
+	web, err := ParseSteamID3("[U:1:22202:4]")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if got := web.Account(); got != NewIndividual(UniversePublic, 22202) {
+		t.Fatalf("expected the desktop account, got %+v", got)
+	}
+
+	lobby, err := ParseSteamID3("[L:1:5]")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if got := lobby.Account(); got != lobby {
+		t.Fatalf("expected chat instance flags to be kept, got %+v", got)
+	}
+}
@@ -28,7 +28,7 @@ mkdir -p "$reports_dir_abs"
 rc=0
 (
   cd "$project_dir_abs"
-  "$gosec_bin" -fmt=json -out="${reports_dir_abs}/gosec.json" ./cmd/steamid-service/... ./internal/app/...
+  "$gosec_bin" -fmt=json -out="${reports_dir_abs}/gosec.json" ./cmd/steamid-service/... ./internal/app/... ./pkg/...
 ) || rc=$?
 
 echo "Reporte JSON generado en ${reports_dir_abs}/gosec.json"